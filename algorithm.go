@@ -10,13 +10,9 @@ import (
 	"github.com/tamada/lioss/lib"
 )
 
-/*
-AvailableAlgorithms contains the names of available algorithm for comparing licenses.
-*/
-var AvailableAlgorithms = []string{"1gram", "2gram", "3gram", "4gram", "5gram", "6gram", "7gram", "8gram", "9gram", "wordfreq", "tfidf"}
-
 /*
 Algorithm shows an algorithm for identifying the license.
+After calling Prepare, Parse and Compare must be safe for concurrent use by multiple goroutines.
 */
 type Algorithm interface {
 	Prepare(db *Database) error
@@ -25,6 +21,36 @@ type Algorithm interface {
 	String() string
 }
 
+func init() {
+	builtins := []*AlgorithmEntry{
+		{Name: "kgram", Description: "compares the frequencies of k characters (k=1, ..., 9) by cosine similarity.",
+			DefaultThreshold: 0.75, Match: matchPattern(`^[1-9]gram$`), New: createNGramAlgorithm,
+			BuildNames: []string{"1gram", "2gram", "3gram", "4gram", "5gram", "6gram", "7gram", "8gram", "9gram"}},
+		{Name: "wordfreq", Description: "compares the frequencies of words by cosine similarity.",
+			DefaultThreshold: 0.75, Match: matchPattern(`^wordfreq$`), New: func(string) (Algorithm, error) { return newWordFreq(), nil },
+			BuildNames: []string{"wordfreq"}},
+		{Name: "tfidf", Description: "compares the tf-idf of words by cosine similarity.",
+			DefaultThreshold: 0.75, Match: matchPattern(`^tfidf$`), New: func(string) (Algorithm, error) { return newTfidf(), nil },
+			BuildNames: []string{"tfidf"}},
+		{Name: "kshingle", Description: "compares the sequences of k words (k=1, ..., 9) by Jaccard similarity.",
+			DefaultThreshold: 0.75, Match: matchPattern(`^[1-9]shingle$`), New: createShingleAlgorithm,
+			BuildNames: []string{"3shingle"}},
+		{Name: "kshingle-containment", Description: "scores how much of the sequences of k words of the license are contained.",
+			DefaultThreshold: 0.75, Match: matchPattern(`^[1-9]shingle-containment$`), New: createShingleAlgorithm,
+			BuildNames: []string{"3shingle-containment"}},
+		{Name: "bm25", Description: "ranks by Okapi BM25 (parameters by bm25:k1=<K1>,b=<B>).",
+			DefaultThreshold: 0.75, Match: matchPattern(`^bm25(:.*)?$`), New: createBM25Algorithm,
+			BuildNames: []string{"bm25"}},
+		{Name: "ensemble", Description: "combines the given algorithms (e.g., ensemble:5gram,tfidf).",
+			DefaultThreshold: 0.75, Match: matchPattern(`^ensemble(:.*)?$`), New: createEnsembleAlgorithm},
+	}
+	for _, entry := range builtins {
+		if err := RegisterAlgorithm(entry); err != nil {
+			panic(err)
+		}
+	}
+}
+
 func createNGramAlgorithm(name string) (Algorithm, error) {
 	lowerName := strings.ToLower(name)
 	nString := strings.Replace(lowerName, "gram", "", -1)
@@ -35,22 +61,30 @@ func createNGramAlgorithm(name string) (Algorithm, error) {
 	return newNGram(value), nil
 }
 
+func createShingleAlgorithm(name string) (Algorithm, error) {
+	lowerName := strings.ToLower(name)
+	containmentFlag := strings.HasSuffix(lowerName, "-containment")
+	kString := strings.TrimSuffix(strings.TrimSuffix(lowerName, "-containment"), "shingle")
+	value, err := strconv.Atoi(kString)
+	if err != nil || value < 1 {
+		return nil, fmt.Errorf("%s: invalid algorithm name", name)
+	}
+	return newShingle(value, containmentFlag), nil
+}
+
 /*
-NewAlgorithm create an instance of Algorithm.
-Available values are [1-9]gram, and tfidf.
+NewAlgorithm create an instance of Algorithm by the registered algorithms.
+Available values are [1-9]gram, wordfreq, tfidf, [1-9]shingle, [1-9]shingle-containment, bm25, ensemble,
+and the algorithms registered by RegisterAlgorithm.
+The parameters of bm25 are given by the name, such as "bm25:k1=1.5,b=0.5".
+The ensemble algorithm combines the given algorithms, such as "ensemble:5gram,tfidf,wordfreq".
 */
 func NewAlgorithm(name string) (Algorithm, error) {
-	lowerName := strings.ToLower(name)
-	if strings.HasSuffix(lowerName, "gram") {
-		return createNGramAlgorithm(name)
-	}
-	switch lowerName {
-	case "wordfreq":
-		return newWordFreq(), nil
-	case "tfidf":
-		return newTfidf(), nil
+	entry, ok := FindAlgorithm(name)
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown algorithm", strings.ToLower(name))
 	}
-	return nil, fmt.Errorf("%s: unknown algorithm", lowerName)
+	return entry.New(name)
 }
 
 func readFully(reader io.Reader) (string, error) {
@@ -11,9 +11,16 @@ func TestCreateComparator(t *testing.T) {
 	}{
 		{"5gram", true},
 		{"kgram", false},
+		{"10gram", false},
 		{"hoge", false},
 		{"wordfreq", true},
 		{"tfidf", true},
+		{"bm25", true},
+		{"ensemble:5gram,tfidf", true},
+		{"3shingle", true},
+		{"4shingle-containment", true},
+		{"kshingle", false},
+		{"0shingle", false},
 	}
 	for _, td := range testdata {
 		comparator, err := NewAlgorithm(td.giveString)
@@ -0,0 +1,147 @@
+package lioss
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/tamada/lioss/lib"
+)
+
+/*
+Ambiguity shows that the most probable license of a license file is hardly distinguishable from the second one,
+that is, the margin of their probabilities is less than Identifier.MinMargin.
+Candidates shows the license expressions of the first and the second candidates.
+Clauses shows the texts distinguishing the candidates, and it is empty if no such texts are known.
+*/
+type Ambiguity struct {
+	Candidates []string  `json:"candidates"`
+	Margin     float64   `json:"margin"`
+	Clauses    []*Clause `json:"clauses,omitempty"`
+}
+
+/*
+Clause is a text which only one of the ambiguous candidates has.
+Found shows whether the license file contains the text.
+*/
+type Clause struct {
+	License string `json:"license"`
+	Text    string `json:"text"`
+	Found   bool   `json:"found"`
+}
+
+/*
+knownClause is a clause of the licenses whose names match the pattern.
+The clauses appearing in the canonical texts of both candidates (e.g., "or (at your option) any later version" in the appendix of GPL)
+do not distinguish them, therefore, the version grants of the GNU licenses are left to the grant scanner (see GrantFinder).
+*/
+type knownClause struct {
+	pattern *regexp.Regexp
+	text    string
+}
+
+var knownClauses = []*knownClause{
+	{regexp.MustCompile(`(?i)^BSD-3-Clause`), "may be used to endorse or promote products derived from this software without specific prior written permission"},
+	{regexp.MustCompile(`(?i)^BSD-4-Clause`), "All advertising materials mentioning features or use of this software must display the following acknowledgement"},
+	{regexp.MustCompile(`(?i)^AGPL`), "Remote Network Interaction"},
+}
+
+const (
+	/*minClauseWords is the minimum number of words of the clauses from the differences of canonical texts.*/
+	minClauseWords = 5
+	/*maxClauseWords is the maximum number of words for showing the clauses, and the longer ones are truncated.*/
+	maxClauseWords = 40
+	/*maxDiffClauses is the maximum number of clauses from the differences of canonical texts, for each candidate.*/
+	maxDiffClauses = 3
+)
+
+/*
+detectAmbiguity returns the ambiguity of the given results (sorted in descending order), and nil if the results are not ambiguous.
+The distinguishing clauses come from the known clauses, and the differences of the canonical texts, if the database has them (see Database.PutText).
+*/
+func (identifier *Identifier) detectAmbiguity(results []*Result, text string) *Ambiguity {
+	if identifier.MinMargin <= 0 || len(results) < 2 {
+		return nil
+	}
+	first, second := results[0], results[1]
+	margin := first.Probability - second.Probability
+	if margin >= identifier.MinMargin {
+		return nil
+	}
+	target := wordKeys(text)
+	clauses := append(findKnownClauses(first.Name, second.Name, target), identifier.diffClauses(first.Name, second.Name, target)...)
+	return &Ambiguity{Candidates: []string{first.Expression(), second.Expression()}, Margin: margin, Clauses: clauses}
+}
+
+func findKnownClauses(name1, name2, target string) []*Clause {
+	clauses := []*Clause{}
+	for _, clause := range knownClauses {
+		matched1, matched2 := clause.pattern.MatchString(name1), clause.pattern.MatchString(name2)
+		if matched1 == matched2 {
+			continue
+		}
+		name := name1
+		if matched2 {
+			name = name2
+		}
+		clauses = append(clauses, newClause(name, strings.Fields(clause.text), target))
+	}
+	return clauses
+}
+
+func (identifier *Identifier) diffClauses(name1, name2, target string) []*Clause {
+	text1, ok1 := identifier.Database.Text(name1)
+	text2, ok2 := identifier.Database.Text(name2)
+	if !ok1 || !ok2 {
+		return []*Clause{}
+	}
+	clauses := []*Clause{}
+	counts := map[string]int{}
+	for _, op := range lib.WordDiff(text2, text1) {
+		words := strings.Fields(op.Text)
+		name := name1
+		if op.Kind == lib.DiffDelete {
+			name = name2
+		}
+		if op.Kind == lib.DiffEqual || len(words) < minClauseWords || counts[name] >= maxDiffClauses {
+			continue
+		}
+		counts[name]++
+		clauses = append(clauses, newClause(name, words, target))
+	}
+	return clauses
+}
+
+func newClause(name string, words []string, target string) *Clause {
+	found := strings.Contains(" "+target+" ", " "+wordKeys(strings.Join(words, " "))+" ")
+	if len(words) > maxClauseWords {
+		words = append(words[:maxClauseWords:maxClauseWords], "...")
+	}
+	return &Clause{License: name, Text: strings.Join(words, " "), Found: found}
+}
+
+/*
+wordKeys returns the words of the given text ignoring the cases and the punctuations around them, joined by a space.
+*/
+func wordKeys(text string) string {
+	keys := []string{}
+	for _, word := range strings.Fields(text) {
+		key := strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+			return unicode.IsPunct(r) || unicode.IsSymbol(r)
+		}))
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return strings.Join(keys, " ")
+}
+
+/*
+top returns the first TopN results, or all of the results if TopN is not positive.
+*/
+func (identifier *Identifier) top(results []*Result) []*Result {
+	if identifier.TopN <= 0 || len(results) <= identifier.TopN {
+		return results
+	}
+	return results[:identifier.TopN]
+}
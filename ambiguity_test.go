@@ -0,0 +1,112 @@
+package lioss
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func identifyProject2(t *testing.T, db *Database, topN int, minMargin float64) *FileResult {
+	identifier, _ := NewIdentifier("5gram", 0.75, db)
+	identifier.TopN = topN
+	identifier.MinMargin = minMargin
+	project, _ := NewProject("testdata/project2")
+	defer project.Close()
+	result := identifier.IdentifyProject(project)
+	if result.Err != nil || len(result.Files) != 1 {
+		t.Fatalf("IdentifyProject failed: %v", result.Err)
+	}
+	return result.Files[0]
+}
+
+func TestDetectAmbiguity(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	testdata := []struct {
+		minMargin      float64
+		wontAmbiguous  bool
+		wontCandidates []string
+	}{
+		{0.0, false, nil},
+		{0.005, false, nil},
+		{0.1, true, []string{"GPLv3.0", "AGPLv3.0"}},
+	}
+	for _, td := range testdata {
+		ambiguity := identifyProject2(t, db, 0, td.minMargin).Ambiguity
+		if (ambiguity != nil) != td.wontAmbiguous {
+			t.Errorf("min margin %f: ambiguity did not match, wont %v, got %v", td.minMargin, td.wontAmbiguous, ambiguity)
+			continue
+		}
+		if ambiguity == nil {
+			continue
+		}
+		if len(ambiguity.Candidates) != 2 || ambiguity.Candidates[0] != td.wontCandidates[0] || ambiguity.Candidates[1] != td.wontCandidates[1] {
+			t.Errorf("candidates did not match, wont %v, got %v", td.wontCandidates, ambiguity.Candidates)
+		}
+		if len(ambiguity.Clauses) != 1 || ambiguity.Clauses[0].License != "AGPLv3.0" || ambiguity.Clauses[0].Found {
+			t.Errorf("clauses did not match, got %v", ambiguity.Clauses)
+		}
+	}
+}
+
+func TestDiffClauses(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	for _, name := range []string{"GPLv3.0", "AGPLv3.0"} {
+		data, _ := ioutil.ReadFile("data/misc/" + name)
+		db.PutText(name, string(data))
+	}
+	ambiguity := identifyProject2(t, db, 0, 0.1).Ambiguity
+	counts := map[string]int{}
+	for _, clause := range ambiguity.Clauses {
+		counts[clause.License]++
+		if clause.License == "AGPLv3.0" && clause.Found {
+			t.Errorf("clause of AGPLv3.0 was found in GPLv3.0: %s", clause.Text)
+		}
+	}
+	if counts["AGPLv3.0"] < 2 || counts["AGPLv3.0"] > maxDiffClauses+1 || counts["GPLv3.0"] > maxDiffClauses {
+		t.Errorf("the number of clauses did not match, got %v", counts)
+	}
+}
+
+func TestTopN(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	testdata := []struct {
+		topN     int
+		wontSize int
+	}{
+		{0, 5},
+		{1, 1},
+		{3, 3},
+		{10, 5},
+	}
+	for _, td := range testdata {
+		file := identifyProject2(t, db, td.topN, 0.1)
+		if len(file.Results) != td.wontSize {
+			t.Errorf("top %d: size of results did not match, wont %d, got %d", td.topN, td.wontSize, len(file.Results))
+		}
+		if file.Ambiguity == nil {
+			t.Errorf("top %d: ambiguity was not detected", td.topN)
+		}
+	}
+}
+
+func TestWordKeys(t *testing.T) {
+	testdata := []struct {
+		give string
+		wont string
+	}{
+		{"Hello, World!", "hello world"},
+		{"  (at your option)  any\nlater version.", "at your option any later version"},
+		{"-- * --", ""},
+	}
+	for _, td := range testdata {
+		if got := wordKeys(td.give); got != td.wont {
+			t.Errorf("wordKeys(%s) did not match, wont %s, got %s", td.give, td.wont, got)
+		}
+	}
+}
+
+func TestNoKnownClausesForVersionGrants(t *testing.T) {
+	clauses := findKnownClauses("GPL-2.0-only", "GPL-2.0-or-later", wordKeys("or (at your option) any later version"))
+	if len(clauses) != 0 {
+		t.Errorf("version grants should not be the known clauses, got %v", clauses)
+	}
+}
@@ -0,0 +1,136 @@
+package lioss
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/tamada/lioss/lib"
+)
+
+/*
+FileResult shows the identified results of a license file.
+Copyrights shows the copyright notices found in the license file.
+Ambiguity is not nil if the first and the second candidates are hardly distinguishable (see Identifier.MinMargin).
+*/
+type FileResult struct {
+	File       LicenseFile
+	Results    []*Result
+	Copyrights []*lib.Copyright
+	Ambiguity  *Ambiguity
+}
+
+/*
+ProjectResult shows the identified results of a project.
+Files are sorted by the ids of license files, and Err shows the error occurred in identifying the project.
+*/
+type ProjectResult struct {
+	Project Project
+	Files   []*FileResult
+	Err     error
+}
+
+/*
+ResultMap returns the results in the form of the return value of Identifier.Identify.
+*/
+func (pr *ProjectResult) ResultMap() map[LicenseFile][]*Result {
+	resultMap := map[LicenseFile][]*Result{}
+	for _, file := range pr.Files {
+		resultMap[file.File] = file.Results
+	}
+	return resultMap
+}
+
+/*
+IdentifyProject identifies the licenses of the given project, and returns the result with the project.
+*/
+func (identifier *Identifier) IdentifyProject(project Project) *ProjectResult {
+	return identifier.IdentifyProjectContext(context.Background(), project)
+}
+
+/*
+IdentifyProjectContext identifies the licenses of the given project, and returns the result with the project.
+When ctx is done, the identification stops, and Err of the result is ctx.Err().
+*/
+func (identifier *Identifier) IdentifyProjectContext(ctx context.Context, project Project) *ProjectResult {
+	result := &ProjectResult{Project: project, Files: []*FileResult{}}
+	identifier.notify(&Event{Type: ProjectOpened, Project: project})
+	ids, err := LicenseIDsContext(ctx, project)
+	if err != nil {
+		result.Err = err
+		identifier.notify(&Event{Type: ErrorOccurred, Project: project, Err: err})
+	}
+	for _, id := range ids {
+		identifier.notify(&Event{Type: LicenseFileFound, Project: project, ID: id})
+	}
+	scanner := newGrantScanner(ctx, project)
+	for _, id := range ids {
+		file, err := identifier.identifyEach(ctx, project, id, scanner)
+		if err != nil {
+			result.Err = err
+			identifier.notify(&Event{Type: ErrorOccurred, Project: project, ID: id, Err: err})
+			break
+		}
+		result.Files = append(result.Files, file)
+		identifier.notify(&Event{Type: FileIdentified, Project: project, ID: id, File: file})
+	}
+	sort.Slice(result.Files, func(i, j int) bool {
+		return result.Files[i].File.ID() < result.Files[j].File.ID()
+	})
+	identifier.notify(&Event{Type: ProjectIdentified, Project: project, Result: result})
+	return result
+}
+
+/*
+IdentifyAll identifies the licenses of the given projects by jobs goroutines.
+If jobs is less than 1, the number of CPUs is used.
+The order of the resultant slice is the same as the given projects.
+When ctx is canceled, the projects not yet identified, and being identified have ctx.Err() as their Err,
+and this method returns ctx.Err().
+The caller is responsible for closing the given projects.
+*/
+func (identifier *Identifier) IdentifyAll(ctx context.Context, projects []Project, jobs int) ([]*ProjectResult, error) {
+	results := make([]*ProjectResult, len(projects))
+	indexes := make(chan int)
+	wg := new(sync.WaitGroup)
+	for i := 0; i < numberOfJobs(jobs, len(projects)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = identifier.IdentifyProjectContext(ctx, projects[index])
+			}
+		}()
+	}
+	err := dispatch(ctx, indexes, len(projects))
+	wg.Wait()
+	for i, result := range results {
+		if result == nil {
+			results[i] = &ProjectResult{Project: projects[i], Files: []*FileResult{}, Err: err}
+		}
+	}
+	return results, err
+}
+
+func dispatch(ctx context.Context, indexes chan<- int, size int) error {
+	defer close(indexes)
+	for i := 0; i < size; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case indexes <- i:
+		}
+	}
+	return nil
+}
+
+func numberOfJobs(jobs, size int) int {
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+	if jobs > size {
+		return size
+	}
+	return jobs
+}
@@ -0,0 +1,101 @@
+package lioss
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestIdentifyAll(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	identifier, _ := NewIdentifier("6gram", 0.75, db)
+	paths := []string{"testdata/project3.jar", "testdata/project1", "testdata/project4", "testdata/project3", "testdata/project2"}
+	projects := []Project{}
+	for _, path := range paths {
+		project, _ := NewProject(path)
+		defer project.Close()
+		projects = append(projects, project)
+	}
+	results, err := identifier.IdentifyAll(context.Background(), projects, 3)
+	if err != nil {
+		t.Fatalf("IdentifyAll failed: %s", err.Error())
+	}
+	wontFileCounts := []int{2, 1, 0, 2, 1}
+	for i, result := range results {
+		if result.Project.BasePath() != paths[i] {
+			t.Errorf("order of results did not match, wont %s, got %s", paths[i], result.Project.BasePath())
+		}
+		if len(result.Files) != wontFileCounts[i] {
+			t.Errorf("%s: file count did not match, wont %d, got %d", paths[i], wontFileCounts[i], len(result.Files))
+		}
+	}
+	if results[0].Files[0].File.ID() != "project3/license" || results[0].Files[0].Results[0].Name != "Apache-License-2.0" {
+		t.Errorf("result of %s did not match, got %v", paths[0], results[0].Files[0].Results)
+	}
+}
+
+func TestIdentifyAllCanceled(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	identifier, _ := NewIdentifier("5gram", 0.75, db)
+	project, _ := NewProject("testdata/project1")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := identifier.IdentifyAll(ctx, []Project{project, project, project}, 1)
+	if err != context.Canceled {
+		t.Errorf("IdentifyAll with canceled context did not return context.Canceled, got %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("size of results did not match, wont 3, got %d", len(results))
+	}
+	for _, result := range results {
+		if result.Err == nil && len(result.Files) != 1 {
+			t.Errorf("result is neither identified nor canceled")
+		}
+	}
+}
+
+func TestConcurrentCompare(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	for _, algorithmName := range []string{"5gram", "wordfreq", "tfidf"} {
+		identifier, _ := NewIdentifier(algorithmName, 0.75, db)
+		license, _, _ := identifier.readLicense(createLicenseFile("data/misc/MIT"))
+		wont, _ := identifier.identify(context.Background(), license)
+		wg := new(sync.WaitGroup)
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				got, _ := identifier.identify(context.Background(), license)
+				if len(got) != len(wont) || math.Abs(got[0].Probability-wont[0].Probability) > 1e-9 {
+					t.Errorf("%s: concurrent identify did not match", algorithmName)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func TestIdentifyProjectCopyrights(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	identifier, _ := NewIdentifier("5gram", 0.75, db)
+	testdata := []struct {
+		givePath   string
+		wontHolder string
+	}{
+		{"testdata/project1", "author name <project_url>"},
+		{"testdata/project2", "Free Software Foundation, Inc. <https://fsf.org/>"},
+	}
+	for _, td := range testdata {
+		project, _ := NewProject(td.givePath)
+		result := identifier.IdentifyProject(project)
+		project.Close()
+		if result.Err != nil || len(result.Files) != 1 {
+			t.Fatalf("%s: identifying failed: %v", td.givePath, result.Err)
+		}
+		copyrights := result.Files[0].Copyrights
+		if len(copyrights) != 1 || copyrights[0].Holder != td.wontHolder {
+			t.Errorf("%s: copyrights did not match, wont %s, got %v", td.givePath, td.wontHolder, copyrights)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package lioss
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+DefaultBM25K1 is the default value of k1 parameter of BM25, which controls the saturation of term frequencies.
+*/
+const DefaultBM25K1 = 1.2
+
+/*
+DefaultBM25B is the default value of b parameter of BM25, which controls the normalization by the document length.
+*/
+const DefaultBM25B = 0.75
+
+/*
+Parameterized shows the algorithm which has tunable parameters.
+The builders of the database store the parameters into the database, and the algorithm reads them in Prepare.
+*/
+type Parameterized interface {
+	Parameters() map[string]float64
+}
+
+/*
+bm25 is an implementation type of Algorithm by Okapi BM25.
+The corpus statistics (the number of documents, document frequencies, and the average document length) are computed in Prepare.
+The parameters are given by the algorithm name (e.g., "bm25:k1=1.5,b=0.5"), the database, or the default values, in this order.
+*/
+type bm25 struct {
+	k1            float64
+	b             float64
+	given         map[string]bool
+	documentCount int
+	averageLength float64
+	frequencies   map[string]int
+	selfScores    map[string]float64
+}
+
+func newBM25() *bm25 {
+	return &bm25{k1: DefaultBM25K1, b: DefaultBM25B, given: map[string]bool{}, frequencies: map[string]int{}, selfScores: map[string]float64{}}
+}
+
+func createBM25Algorithm(name string) (Algorithm, error) {
+	bm25 := newBM25()
+	items := strings.SplitN(name, ":", 2)
+	if strings.ToLower(items[0]) != "bm25" {
+		return nil, fmt.Errorf("%s: unknown algorithm", name)
+	}
+	if len(items) == 1 {
+		return bm25, nil
+	}
+	for _, param := range strings.Split(items[1], ",") {
+		if err := bm25.setParameter(param); err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err.Error())
+		}
+	}
+	return bm25, nil
+}
+
+func (bm25 *bm25) setParameter(param string) error {
+	keyValue := strings.SplitN(param, "=", 2)
+	if len(keyValue) != 2 {
+		return fmt.Errorf("%s: invalid parameter", param)
+	}
+	value, err := strconv.ParseFloat(keyValue[1], 64)
+	if err != nil || value < 0 {
+		return fmt.Errorf("%s: invalid parameter value", param)
+	}
+	key := strings.ToLower(keyValue[0])
+	switch key {
+	case "k1":
+		bm25.k1 = value
+	case "b":
+		if value > 1 {
+			return fmt.Errorf("%s: b must be 0.0 to 1.0", param)
+		}
+		bm25.b = value
+	default:
+		return fmt.Errorf("%s: unknown parameter", keyValue[0])
+	}
+	bm25.given[key] = true
+	return nil
+}
+
+func (bm25 *bm25) String() string {
+	return "bm25"
+}
+
+/*
+Parameters returns k1 and b parameters of BM25.
+*/
+func (bm25 *bm25) Parameters() map[string]float64 {
+	return map[string]float64{"k1": bm25.k1, "b": bm25.b}
+}
+
+/*
+Prepare of bm25 reads the parameters stored in the database, and computes the corpus statistics.
+*/
+func (bm25 *bm25) Prepare(db *Database) error {
+	bm25.readParameters(db.Parameters[bm25.String()])
+	licenses := db.Entries(bm25.String())
+	bm25.documentCount = len(licenses)
+	bm25.frequencies = map[string]int{}
+	total := 0
+	for _, license := range licenses {
+		for term := range license.Frequencies {
+			bm25.frequencies[term]++
+		}
+		total += license.total()
+	}
+	if len(licenses) > 0 {
+		bm25.averageLength = float64(total) / float64(len(licenses))
+	}
+	bm25.selfScores = map[string]float64{}
+	for _, license := range licenses {
+		bm25.selfScores[license.Name] = bm25.score(license, license)
+	}
+	return nil
+}
+
+func (bm25 *bm25) readParameters(params map[string]float64) {
+	if value, ok := params["k1"]; ok && !bm25.given["k1"] {
+		bm25.k1 = value
+	}
+	if value, ok := params["b"]; ok && !bm25.given["b"] {
+		bm25.b = value
+	}
+}
+
+/*
+Parse parses given data and create an instance of License by bm25.
+*/
+func (bm25 *bm25) Parse(reader io.Reader, licenseName string) (*License, error) {
+	result, err := readFully(reader)
+	if err != nil {
+		return nil, err
+	}
+	return buildWordFreqLicense(licenseName, result)
+}
+
+func (bm25 *bm25) idf(term string) float64 {
+	df := float64(bm25.frequencies[term])
+	n := float64(bm25.documentCount)
+	return math.Log((n-df+0.5)/(df+0.5) + 1)
+}
+
+/*
+score computes BM25 score of the document for the query.
+*/
+func (bm25 *bm25) score(query, document *License) float64 {
+	lengthRatio := 1.0
+	if bm25.averageLength > 0 {
+		lengthRatio = float64(document.total()) / bm25.averageLength
+	}
+	norm := bm25.k1 * (1 - bm25.b + bm25.b*lengthRatio)
+	sum := 0.0
+	for term := range query.Frequencies {
+		freq := float64(document.Frequencies[term])
+		if freq > 0 {
+			sum += bm25.idf(term) * freq * (bm25.k1 + 1) / (freq + norm)
+		}
+	}
+	return sum
+}
+
+func (bm25 *bm25) selfScore(license *License) float64 {
+	if score, ok := bm25.selfScores[license.Name]; ok {
+		return score
+	}
+	return bm25.score(license, license)
+}
+
+/*
+Compare computes the similarity of the given licenses by BM25.
+license1 is treated as the query, and license2 as the document (the license in the database).
+The score is normalized by the score of license2 for itself, therefore, the resultant value is in the range of 0.0 to 1.0.
+*/
+func (bm25 *bm25) Compare(license1, license2 *License) float64 {
+	self := bm25.selfScore(license2)
+	if self == 0 {
+		return 0
+	}
+	return math.Min(bm25.score(license1, license2)/self, 1.0)
+}
@@ -0,0 +1,107 @@
+package lioss
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestCreateBM25(t *testing.T) {
+	testdata := []struct {
+		giveString  string
+		successFlag bool
+		wontK1      float64
+		wontB       float64
+	}{
+		{"bm25", true, DefaultBM25K1, DefaultBM25B},
+		{"bm25:k1=2.0", true, 2.0, DefaultBM25B},
+		{"bm25:k1=1.5,b=0.5", true, 1.5, 0.5},
+		{"bm25:b=1.5", false, 0, 0},
+		{"bm25:c=1", false, 0, 0},
+		{"bm25:k1", false, 0, 0},
+		{"bm25x", false, 0, 0},
+	}
+	for _, td := range testdata {
+		algorithm, err := NewAlgorithm(td.giveString)
+		if (err == nil) != td.successFlag {
+			t.Errorf("%s: success flag did not match, wont %v, got %v", td.giveString, td.successFlag, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		params := algorithm.(Parameterized).Parameters()
+		if params["k1"] != td.wontK1 || params["b"] != td.wontB {
+			t.Errorf("%s: parameters did not match, wont k1=%f, b=%f, got %v", td.giveString, td.wontK1, td.wontB, params)
+		}
+		if algorithm.String() != "bm25" {
+			t.Errorf("%s: name did not match, got %s", td.giveString, algorithm.String())
+		}
+	}
+}
+
+func TestBM25ParametersFromDatabase(t *testing.T) {
+	testdata := []struct {
+		giveString string
+		wontK1     float64
+	}{
+		{"bm25", 2.5},
+		{"bm25:k1=1.0", 1.0},
+	}
+	db := NewDatabase()
+	db.Parameters = map[string]map[string]float64{"bm25": {"k1": 2.5, "b": 0.3}}
+	for _, td := range testdata {
+		algorithm, _ := NewAlgorithm(td.giveString)
+		algorithm.Prepare(db)
+		params := algorithm.(Parameterized).Parameters()
+		if params["k1"] != td.wontK1 || params["b"] != 0.3 {
+			t.Errorf("%s: parameters did not match, wont k1=%f, b=0.3, got %v", td.giveString, td.wontK1, params)
+		}
+	}
+}
+
+func TestStoreParameters(t *testing.T) {
+	db := NewDatabase()
+	db.StoreParameters(newNGram(5))
+	if len(db.Parameters) != 0 {
+		t.Errorf("parameters of 5gram should not be stored, got %v", db.Parameters)
+	}
+	testdata := []struct {
+		giveName string
+		wontK1   float64
+		wontB    float64
+	}{
+		{"bm25", DefaultBM25K1, DefaultBM25B},
+		{"bm25:k1=3", 3, DefaultBM25B},
+		{"bm25:b=0.5", 3, 0.5},
+		{"bm25", 3, 0.5},
+	}
+	for _, td := range testdata {
+		algorithm, _ := NewAlgorithm(td.giveName)
+		db.StoreParameters(algorithm)
+		if params := db.Parameters["bm25"]; params["k1"] != td.wontK1 || params["b"] != td.wontB {
+			t.Errorf("%s: stored parameters did not match, wont k1=%g, b=%g, got %v", td.giveName, td.wontK1, td.wontB, params)
+		}
+	}
+}
+
+func TestBM25Compare(t *testing.T) {
+	algorithm := newBM25()
+	db := buildMinHashTestDB(t, algorithm)
+	algorithm.Prepare(db)
+	data, _ := ioutil.ReadFile("data/misc/Apache-License-2.0")
+	notice := strings.Repeat("This product includes software developed by the Example Foundation.\n", 30)
+	target, _ := algorithm.Parse(strings.NewReader(string(data)+notice), "LICENSE")
+	for _, license := range db.Entries("bm25") {
+		got := algorithm.Compare(target, license)
+		if got < 0 || got > 1 {
+			t.Errorf("similarity with %s is out of range, got %f", license.Name, got)
+		}
+		if license.Name == "Apache-License-2.0" && got < 0.99 {
+			t.Errorf("similarity with %s should be high even if NOTICE is appended, got %f", license.Name, got)
+		}
+		if self := algorithm.Compare(license, license); self < 0.9999 {
+			t.Errorf("similarity of %s with itself should be 1.0, got %f", license.Name, self)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package lioss
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+/*
+DefaultFalsePositiveRate is the default target false-positive rate for calibrating the thresholds.
+*/
+const DefaultFalsePositiveRate = 0.01
+
+/*
+Calibrate computes the threshold of the given algorithm achieving the given false-positive rate on the database itself.
+The false positives are the pairs of different licenses in the database whose similarity is higher than or equal to the threshold.
+The pairs of the licenses having the same terms (e.g., GPL-3.0-only, and GPL-3.0-or-later) are not counted.
+The resultant threshold is stored into the database.
+This method returns an error if the database has less than two licenses for the algorithm.
+*/
+func (db *Database) Calibrate(algorithmName string, falsePositiveRate float64) (float64, error) {
+	if falsePositiveRate < 0 || falsePositiveRate > 1 {
+		return 0, fmt.Errorf("%f: false-positive rate must be 0.0 to 1.0", falsePositiveRate)
+	}
+	algorithm, err := NewAlgorithm(algorithmName)
+	if err != nil {
+		return 0, err
+	}
+	if err := algorithm.Prepare(db); err != nil {
+		return 0, err
+	}
+	scores := pairScores(algorithm, db.Entries(algorithm.String()))
+	if len(scores) == 0 {
+		return 0, fmt.Errorf("%s: no pairs of licenses for calibration", algorithmName)
+	}
+	threshold := thresholdOf(scores, falsePositiveRate)
+	if db.Thresholds == nil {
+		db.Thresholds = map[string]float64{}
+	}
+	db.Thresholds[algorithm.String()] = threshold
+	return threshold, nil
+}
+
+/*
+CalibrateAll calibrates the thresholds of all of available algorithms in the database, by Calibrate.
+The algorithms without enough licenses are skipped.
+*/
+func (db *Database) CalibrateAll(falsePositiveRate float64) error {
+	for _, name := range AvailableAlgorithms {
+		if len(db.Entries(name)) < 2 {
+			continue
+		}
+		if _, err := db.Calibrate(name, falsePositiveRate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+DefaultThreshold returns the calibrated threshold of the given algorithm in the database.
+If the database has no calibrated threshold for the algorithm, this method returns the default threshold of the algorithm.
+*/
+func (db *Database) DefaultThreshold(algorithmName string) float64 {
+	if threshold, ok := db.Thresholds[algorithmName]; ok {
+		return threshold
+	}
+	return DefaultThreshold(algorithmName)
+}
+
+/*
+thresholdOf returns the minimum threshold which the ratio of scores higher than or equal to it is at most the given rate.
+*/
+func thresholdOf(scores []float64, falsePositiveRate float64) float64 {
+	sort.Sort(sort.Reverse(sort.Float64Slice(scores)))
+	allowed := int(math.Floor(falsePositiveRate * float64(len(scores))))
+	if allowed >= len(scores) {
+		return 0
+	}
+	return math.Min(math.Nextafter(scores[allowed], math.Inf(1)), 1.0)
+}
+
+/*
+maxCalibrationPairs is the maximum number of pairs for calibration.
+If the database has more pairs, the pairs are sampled randomly (with the fixed seed for reproducibility).
+*/
+const maxCalibrationPairs = 20000
+
+type licensePair struct {
+	license1 *License
+	license2 *License
+}
+
+func calibrationPairs(licenses []*License) []*licensePair {
+	pairs := []*licensePair{}
+	size := len(licenses)
+	if size*(size-1) <= maxCalibrationPairs {
+		for i := range licenses {
+			for j := range licenses {
+				pairs = appendPair(pairs, licenses[i], licenses[j])
+			}
+		}
+		return pairs
+	}
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; len(pairs) < maxCalibrationPairs && trial < maxCalibrationPairs*2; trial++ {
+		pairs = appendPair(pairs, licenses[random.Intn(size)], licenses[random.Intn(size)])
+	}
+	return pairs
+}
+
+func appendPair(pairs []*licensePair, license1, license2 *License) []*licensePair {
+	if license1 == license2 || isSameFrequencies(license1.Frequencies, license2.Frequencies) {
+		return pairs
+	}
+	return append(pairs, &licensePair{license1: license1, license2: license2})
+}
+
+/*
+pairScores computes the similarities of the pairs of different licenses in parallel.
+*/
+func pairScores(algorithm Algorithm, licenses []*License) []float64 {
+	pairs := calibrationPairs(licenses)
+	scores := make([]float64, len(pairs))
+	indexes := make(chan int)
+	wg := new(sync.WaitGroup)
+	for i := 0; i < numberOfJobs(runtime.NumCPU(), len(pairs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				scores[index] = algorithm.Compare(pairs[index].license1, pairs[index].license2)
+			}
+		}()
+	}
+	for i := range pairs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return scores
+}
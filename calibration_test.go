@@ -0,0 +1,90 @@
+package lioss
+
+import (
+	"math"
+	"testing"
+)
+
+func TestThresholdOf(t *testing.T) {
+	scores := []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 0.95}
+	testdata := []struct {
+		scores            []float64
+		falsePositiveRate float64
+		wont              float64
+	}{
+		{scores, 0.1, 0.9},
+		{scores, 0.0, 0.95},
+		{scores, 0.35, 0.7},
+		{[]float64{0.1, 1.0}, 0.0, 1.0},
+		{[]float64{0.1, 0.2}, 1.0, 0.0},
+	}
+	for _, td := range testdata {
+		got := thresholdOf(append([]float64{}, td.scores...), td.falsePositiveRate)
+		if math.Abs(got-td.wont) > 1e-9 {
+			t.Errorf("thresholdOf(%v, %f) did not match, wont %f, got %f", td.scores, td.falsePositiveRate, td.wont, got)
+		}
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	algorithm, _ := NewAlgorithm("5gram")
+	db := buildMinHashTestDB(t, algorithm)
+	threshold, err := db.Calibrate("5gram", 0.01)
+	if err != nil {
+		t.Fatalf("calibrate failed: %s", err.Error())
+	}
+	if db.Thresholds["5gram"] != threshold || db.DefaultThreshold("5gram") != threshold {
+		t.Errorf("calibrated threshold was not stored, got %v", db.Thresholds)
+	}
+	pairs := pairScores(algorithm, db.Entries("5gram"))
+	count := 0
+	for _, score := range pairs {
+		if score >= threshold {
+			count++
+		}
+	}
+	if rate := float64(count) / float64(len(pairs)); rate > 0.01 {
+		t.Errorf("false-positive rate did not satisfy the target, wont <= 0.01, got %f", rate)
+	}
+	if loose, _ := db.Calibrate("5gram", 0.1); loose >= threshold {
+		t.Errorf("threshold by higher false-positive rate should be lower, %f >= %f", loose, threshold)
+	}
+	if db.DefaultThreshold("9gram") != 0.75 {
+		t.Errorf("threshold of uncalibrated algorithm should be default, got %f", db.DefaultThreshold("9gram"))
+	}
+}
+
+func TestCalibrateErrors(t *testing.T) {
+	db := NewDatabase()
+	db.Put("5gram", newLicense("license", map[string]int{"abcde": 1}))
+	testdata := []struct {
+		algorithm         string
+		falsePositiveRate float64
+	}{
+		{"5gram", 0.01},
+		{"5gram", 1.5},
+		{"unknown", 0.01},
+	}
+	for _, td := range testdata {
+		if _, err := db.Calibrate(td.algorithm, td.falsePositiveRate); err == nil {
+			t.Errorf("Calibrate(%s, %f) should be failed", td.algorithm, td.falsePositiveRate)
+		}
+	}
+	if err := db.CalibrateAll(0.01); err != nil || len(db.Thresholds) != 0 {
+		t.Errorf("CalibrateAll should skip the algorithms without enough licenses, got %v, %v", err, db.Thresholds)
+	}
+}
+
+func TestMergeThresholds(t *testing.T) {
+	db1 := NewDatabase()
+	db1.Thresholds = map[string]float64{"5gram": 0.9, "tfidf": 0.8}
+	db2 := NewDatabase()
+	db2.Thresholds = map[string]float64{"5gram": 0.95, "9gram": 0.7}
+	merged := db1.Merge(db2)
+	wont := map[string]float64{"5gram": 0.95, "tfidf": 0.8, "9gram": 0.7}
+	for name, threshold := range wont {
+		if merged.Thresholds[name] != threshold {
+			t.Errorf("merged threshold of %s did not match, wont %f, got %f", name, threshold, merged.Thresholds[name])
+		}
+	}
+}
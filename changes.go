@@ -0,0 +1,66 @@
+package lioss
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Changes shows the license names changed by updating the database.
+*/
+type Changes struct {
+	Added    []string
+	Replaced []string
+	Removed  []string
+}
+
+/*
+NewChanges creates an instance of Changes.
+*/
+func NewChanges() *Changes {
+	return &Changes{Added: []string{}, Replaced: []string{}, Removed: []string{}}
+}
+
+/*
+Remove records the given license name as removed.
+*/
+func (changes *Changes) Remove(licenseName string) {
+	changes.add(&changes.Removed, licenseName)
+}
+
+func (changes *Changes) add(names *[]string, licenseName string) {
+	for _, name := range *names {
+		if name == licenseName {
+			return
+		}
+	}
+	*names = append(*names, licenseName)
+}
+
+/*
+IsEmpty returns true if no changes are recorded.
+*/
+func (changes *Changes) IsEmpty() bool {
+	return len(changes.Added) == 0 && len(changes.Replaced) == 0 && len(changes.Removed) == 0
+}
+
+func (changes *Changes) String() string {
+	if changes.IsEmpty() {
+		return "no changes"
+	}
+	lines := []string{}
+	items := []struct {
+		label string
+		names []string
+	}{
+		{"added", changes.Added},
+		{"replaced", changes.Replaced},
+		{"removed", changes.Removed},
+	}
+	for _, item := range items {
+		if len(item.names) > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %s", item.label, strings.Join(item.names, ", ")))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
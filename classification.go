@@ -0,0 +1,195 @@
+package lioss
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+/*
+Category shows the category of licenses, such as permissive, and strong copyleft.
+*/
+type Category string
+
+/*
+The categories of licenses.
+*/
+const (
+	/*PublicDomain shows the licenses dedicating to the public domain, or equivalent (e.g., CC0-1.0, and 0BSD).*/
+	PublicDomain Category = "public-domain"
+	/*Permissive shows the licenses requiring only the attribution (e.g., MIT, and Apache-2.0).*/
+	Permissive Category = "permissive"
+	/*WeakCopyleft shows the licenses requiring the same license for the modified files or the library only (e.g., LGPL-2.1-only, and MPL-2.0).*/
+	WeakCopyleft Category = "weak-copyleft"
+	/*StrongCopyleft shows the licenses requiring the same license for the whole of derivative works (e.g., GPL-3.0-only).*/
+	StrongCopyleft Category = "strong-copyleft"
+	/*NetworkCopyleft shows the strong copyleft licenses covering the use over the network (e.g., AGPL-3.0-only).*/
+	NetworkCopyleft Category = "network-copyleft"
+	/*SourceAvailable shows the proprietary licenses disclosing the source code with restrictions (e.g., SSPL-1.0, and BUSL-1.1).*/
+	SourceAvailable Category = "source-available"
+)
+
+/*
+Categories returns the available categories, in the order of the strength of restrictions.
+*/
+func Categories() []Category {
+	return []Category{PublicDomain, Permissive, WeakCopyleft, StrongCopyleft, NetworkCopyleft, SourceAvailable}
+}
+
+/*
+ParseCategory parses the given string (e.g., "weak-copyleft") to Category.
+*/
+func ParseCategory(name string) (Category, error) {
+	for _, category := range Categories() {
+		if strings.EqualFold(name, string(category)) {
+			return category, nil
+		}
+	}
+	return "", fmt.Errorf("%s: unknown category", name)
+}
+
+/*
+The obligations of licenses.
+*/
+const (
+	Attribution          = "attribution"
+	SourceDisclosure     = "source-disclosure"
+	SameLicense          = "same-license"
+	PatentGrant          = "patent-grant"
+	StateChanges         = "state-changes"
+	NetworkUseDisclosure = "network-use-disclosure"
+)
+
+/*
+The limitations of licenses.
+*/
+const (
+	Liability    = "liability"
+	Warranty     = "warranty"
+	TrademarkUse = "trademark-use"
+	PatentUse    = "patent-use"
+)
+
+/*
+Classification shows the category, the obligations, and the limitations of a license.
+*/
+type Classification struct {
+	Category    Category `json:"category"`
+	Obligations []string `json:"obligations,omitempty"`
+	Limitations []string `json:"limitations,omitempty"`
+}
+
+/*
+HasObligation returns true if the classification has the given obligation.
+*/
+func (classification *Classification) HasObligation(obligation string) bool {
+	return contains(classification.Obligations, obligation)
+}
+
+func contains(slice []string, item string) bool {
+	for _, element := range slice {
+		if element == item {
+			return true
+		}
+	}
+	return false
+}
+
+var knowledgeBase = struct {
+	mutex           sync.RWMutex
+	classifications map[string]*Classification
+}{classifications: map[string]*Classification{}}
+
+/*
+linkingExceptions are the license exceptions permitting to link the libraries without applying the license to the whole works.
+The strong copyleft licenses with them are treated as the weak copyleft licenses.
+*/
+var linkingExceptions = []string{"Classpath-exception-2.0", "GCC-exception-2.0", "GCC-exception-3.1", "LLVM-exception", "Linux-syscall-note", "OpenJDK-assembly-exception-1.0", "Autoconf-exception-3.0", "Bison-exception-2.2"}
+
+/*
+RegisterClassification registers (or overrides) the classification of the given SPDX license ID into the knowledge base.
+*/
+func RegisterClassification(id string, classification *Classification) {
+	knowledgeBase.mutex.Lock()
+	defer knowledgeBase.mutex.Unlock()
+	knowledgeBase.classifications[strings.ToLower(id)] = classification
+}
+
+/*
+Classify returns the classification of the given SPDX license ID from the knowledge base.
+The IDs are matched case-insensitively, and this function returns nil if the ID is not in the knowledge base.
+*/
+func Classify(id string) *Classification {
+	knowledgeBase.mutex.RLock()
+	defer knowledgeBase.mutex.RUnlock()
+	return knowledgeBase.classifications[strings.ToLower(id)]
+}
+
+/*
+ClassifyResult returns the classification of the given result.
+If the result has the linking exception (e.g., Classpath-exception-2.0), the strong copyleft is relaxed into the weak copyleft.
+*/
+func ClassifyResult(result *Result) *Classification {
+	classification := Classify(result.Name)
+	if classification == nil || result.Exception == "" || classification.Category != StrongCopyleft || !containsFold(linkingExceptions, result.Exception) {
+		return classification
+	}
+	return &Classification{Category: WeakCopyleft, Obligations: classification.Obligations, Limitations: classification.Limitations}
+}
+
+func containsFold(slice []string, item string) bool {
+	for _, element := range slice {
+		if strings.EqualFold(element, item) {
+			return true
+		}
+	}
+	return false
+}
+
+func classifyResults(results []*Result) []*Result {
+	for _, result := range results {
+		result.Classification = ClassifyResult(result)
+	}
+	return results
+}
+
+func init() {
+	for _, entry := range knownLicenses {
+		classification := &Classification{Category: entry.category, Obligations: entry.obligations, Limitations: entry.limitations}
+		for _, id := range entry.ids {
+			RegisterClassification(id, classification)
+		}
+	}
+}
+
+var (
+	disclaimers          = []string{Liability, Warranty}
+	trademarkDisclaimers = []string{Liability, Warranty, TrademarkUse}
+	copyleftObligations  = []string{Attribution, SourceDisclosure, SameLicense, StateChanges}
+	copyleft3Obligations = []string{Attribution, SourceDisclosure, SameLicense, StateChanges, PatentGrant}
+)
+
+/*
+knownLicenses is the knowledge base shipped with lioss, keyed by SPDX license IDs (including the deprecated IDs).
+*/
+var knownLicenses = []struct {
+	ids         []string
+	category    Category
+	obligations []string
+	limitations []string
+}{
+	{[]string{"CC0-1.0", "Unlicense"}, PublicDomain, []string{}, disclaimers},
+	{[]string{"0BSD", "MIT-0", "WTFPL"}, PublicDomain, []string{}, []string{}},
+	{[]string{"MIT", "X11", "ISC", "BSD-2-Clause", "BSD-2-Clause-Patent", "NCSA", "PostgreSQL", "Zlib", "BSL-1.0", "curl", "JSON", "Unicode-DFS-2016", "MIT-feh", "MITNFA", "Xnet"}, Permissive, []string{Attribution}, disclaimers},
+	{[]string{"BSD-3-Clause", "BSD-3-Clause-Clear", "BSD-4-Clause", "Python-2.0", "PSF-2.0", "Apache-1.0", "Apache-1.1", "Artistic-2.0", "W3C", "OpenSSL"}, Permissive, []string{Attribution}, trademarkDisclaimers},
+	{[]string{"Apache-2.0", "ECL-2.0"}, Permissive, []string{Attribution, StateChanges, PatentGrant}, trademarkDisclaimers},
+	{[]string{"UPL-1.0", "AFL-3.0"}, Permissive, []string{Attribution, PatentGrant}, trademarkDisclaimers},
+	{[]string{"LGPL-2.0-only", "LGPL-2.0-or-later", "LGPL-2.1-only", "LGPL-2.1-or-later", "LGPL-2.0", "LGPL-2.0+", "LGPL-2.1", "LGPL-2.1+"}, WeakCopyleft, copyleftObligations, disclaimers},
+	{[]string{"LGPL-3.0-only", "LGPL-3.0-or-later", "LGPL-3.0", "LGPL-3.0+"}, WeakCopyleft, copyleft3Obligations, disclaimers},
+	{[]string{"MPL-1.1", "MPL-2.0", "MPL-2.0-no-copyleft-exception", "EPL-1.0", "EPL-2.0", "CDDL-1.0", "CDDL-1.1", "CPL-1.0", "MS-RL", "MS-PL"}, WeakCopyleft, []string{Attribution, SourceDisclosure, SameLicense, PatentGrant}, trademarkDisclaimers},
+	{[]string{"GPL-1.0-only", "GPL-1.0-or-later", "GPL-2.0-only", "GPL-2.0-or-later", "GPL-1.0", "GPL-1.0+", "GPL-2.0", "GPL-2.0+", "Sleepycat"}, StrongCopyleft, copyleftObligations, disclaimers},
+	{[]string{"GPL-3.0-only", "GPL-3.0-or-later", "GPL-3.0", "GPL-3.0+", "EUPL-1.1", "EUPL-1.2"}, StrongCopyleft, copyleft3Obligations, disclaimers},
+	{[]string{"AGPL-1.0-only", "AGPL-1.0-or-later", "AGPL-1.0"}, NetworkCopyleft, append([]string{NetworkUseDisclosure}, copyleftObligations...), disclaimers},
+	{[]string{"AGPL-3.0-only", "AGPL-3.0-or-later", "AGPL-3.0", "OSL-3.0", "RPL-1.5"}, NetworkCopyleft, append([]string{NetworkUseDisclosure}, copyleft3Obligations...), disclaimers},
+	{[]string{"SSPL-1.0", "BUSL-1.1", "Elastic-2.0", "PolyForm-Noncommercial-1.0.0", "PolyForm-Small-Business-1.0.0", "CC-BY-NC-4.0"}, SourceAvailable, []string{Attribution}, disclaimers},
+}
@@ -0,0 +1,87 @@
+package lioss
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	testdata := []struct {
+		giveID       string
+		wontCategory Category
+		wontNil      bool
+	}{
+		{"MIT", Permissive, false},
+		{"apache-2.0", Permissive, false},
+		{"CC0-1.0", PublicDomain, false},
+		{"LGPL-2.1-only", WeakCopyleft, false},
+		{"GPL-2.0+", StrongCopyleft, false},
+		{"AGPL-3.0-or-later", NetworkCopyleft, false},
+		{"SSPL-1.0", SourceAvailable, false},
+		{"Apache-License-2.0", "", true},
+	}
+	for _, td := range testdata {
+		classification := Classify(td.giveID)
+		if (classification == nil) != td.wontNil {
+			t.Errorf("Classify(%s) did not match, wont nil: %v", td.giveID, td.wontNil)
+			continue
+		}
+		if classification != nil && classification.Category != td.wontCategory {
+			t.Errorf("category of %s did not match, wont %s, got %s", td.giveID, td.wontCategory, classification.Category)
+		}
+	}
+}
+
+func TestClassifyResult(t *testing.T) {
+	testdata := []struct {
+		giveResult   *Result
+		wontCategory Category
+	}{
+		{&Result{Name: "GPL-2.0-only"}, StrongCopyleft},
+		{&Result{Name: "GPL-2.0-only", Exception: "Classpath-exception-2.0"}, WeakCopyleft},
+		{&Result{Name: "GPL-2.0-only", Exception: "Unknown-exception"}, StrongCopyleft},
+		{&Result{Name: "Apache-2.0", Exception: "LLVM-exception"}, Permissive},
+	}
+	for _, td := range testdata {
+		classification := ClassifyResult(td.giveResult)
+		if classification.Category != td.wontCategory {
+			t.Errorf("category of %s did not match, wont %s, got %s", td.giveResult.Expression(), td.wontCategory, classification.Category)
+		}
+	}
+	if !Classify("GPL-2.0-only").HasObligation(SameLicense) || Classify("MIT").HasObligation(SourceDisclosure) {
+		t.Errorf("obligations did not match")
+	}
+}
+
+func TestParseCategory(t *testing.T) {
+	for _, category := range Categories() {
+		if got, err := ParseCategory(string(category)); err != nil || got != category {
+			t.Errorf("ParseCategory(%s) did not match, got %s (%v)", category, got, err)
+		}
+	}
+	if _, err := ParseCategory("copyleft"); err == nil {
+		t.Errorf("ParseCategory(copyleft) should be failed")
+	}
+}
+
+func TestResultsAreClassified(t *testing.T) {
+	algorithm, _ := NewAlgorithm("5gram")
+	reader, _ := os.Open("data/misc/MIT")
+	license, _ := algorithm.Parse(reader, "MIT")
+	reader.Close()
+	db := NewDatabase()
+	db.Put("5gram", license)
+	RegisterClassification("Custom-1.0", &Classification{Category: Permissive})
+	db.Put("5gram", newLicense("Custom-1.0", license.Frequencies))
+	identifier, _ := NewIdentifier("5gram", 0.75, db)
+	results, _ := identifier.identify(context.Background(), license)
+	if len(results) != 2 {
+		t.Fatalf("size of results did not match, wont 2, got %d", len(results))
+	}
+	for _, result := range results {
+		if result.Classification == nil || result.Classification.Category != Permissive {
+			t.Errorf("%s: result was not classified, got %v", result.Name, result.Classification)
+		}
+	}
+}
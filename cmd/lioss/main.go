@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
-	"sort"
+	"runtime"
 	"strings"
 
 	flag "github.com/spf13/pflag"
@@ -17,36 +20,89 @@ VERSION shows the version of the lioss.
 const VERSION = "1.0.0"
 
 type liossOptions struct {
-	helpFlag  bool
-	dbtype    string
-	dbPath    string
-	algorithm string
-	threshold float64
+	helpFlag   bool
+	dbtype     string
+	dbPath     string
+	algorithm  string
+	threshold  float64
+	jobs       int
+	exhaustive bool
+	top        int
+	minMargin  float64
+	copyright  bool
+	policy     string
+	format     string
+	template   string
+	progress   bool
+	/* thresholdFlag is true if the threshold is given by the user. */
+	thresholdFlag bool
 }
 
 func helpMessage(appName string) string {
 	app := filepath.Base(appName)
 	return fmt.Sprintf(`%s version %s
 %s [OPTIONS] <PROJECTS...>
+%s serve [SERVE_OPTIONS]
+%s notice [NOTICE_OPTIONS] <PROJECTS...>
+%s report [REPORT_OPTIONS] --html <DIR> <PROJECTS...>
 OPTIONS
         --database-path <PATH>     specifies the database path.
                                    If specifying this option, database-type option is ignored.
         --database-type <TYPE>     specifies the database type. Default is osi.
                                    Available values are: non-osi, osi, deprecated, osi-deprecated, and whole.
     -a, --algorithm <ALGORITHM>    specifies algorithm. Default is 5gram.
-                                   Available values are: kgram, wordfreq, and tfidf.
+                                   Available values are: %s.
     -t, --threshold <THRESHOLD>    specifies threshold of the similarities of license files.
-                                   Each algorithm has default value. Default value is 0.75.
+                                   Default is the calibrated threshold in the database,
+                                   or the default value of each algorithm (0.75).
+    -j, --jobs <JOBS>              specifies the number of projects identified in parallel.
+                                   Default is the number of CPUs.
+        --exhaustive               compares with all of licenses in the database without the MinHash index.
+    -n, --top <N>                  prints the top N results of each license file. Default is 0 (all results).
+        --min-margin <MARGIN>      flags the license file as ambiguous if the margin of probabilities between
+                                   the first and the second results is less than MARGIN. Default is 0.0 (disabled).
+    -c, --copyright                prints the copyright notices found in the license files.
+        --policy <FILE>            evaluates the results by the given policy file (JSON), and prints the violations.
+                                   The exit status is 3 if some licenses require the review, and 4 if denied.
+    -f, --format <FORMAT>          specifies the output format. Default is text.
+                                   Available values are: text, sarif, csv, and markdown.
+        --template <FILE>          prints the results by the given Go text/template file, instead of the format.
+        --progress                 prints the progress of the identification into stderr.
     -h, --help                     prints this message.
 PROJECTS
-    project directories, and/or archive files contains LICENSE file.`, app, VERSION, app)
+    project directories, and/or archive files contains LICENSE file.
+    "-" reads the license text from stdin.
+    The exit status is 5 if some projects cannot be opened or identified.
+ALGORITHMS
+%s
+SERVE_OPTIONS
+    see "%s serve --help".
+NOTICE_OPTIONS
+    see "%s notice --help".
+REPORT_OPTIONS
+    see "%s report --help".`, app, VERSION, app, app, app, app, algorithmNames(), algorithmDescriptions(), app, app, app)
 }
 
-func printResult(project lioss.Project, id string, results []*lioss.Result) {
-	fmt.Printf("%s/%s\n", project.BasePath(), id)
-	for _, result := range results {
-		fmt.Printf("\t%s (%1.4f)\n", result.Name, result.Probability)
+/*
+algorithmNames returns the names of the registered algorithms, such as "kgram, wordfreq, and tfidf".
+*/
+func algorithmNames() string {
+	names := []string{}
+	for _, entry := range lioss.RegisteredAlgorithms() {
+		names = append(names, entry.Name)
+	}
+	if len(names) <= 1 {
+		return strings.Join(names, "")
 	}
+	return fmt.Sprintf("%s, and %s", strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
+
+func algorithmDescriptions() string {
+	lines := []string{}
+	for _, entry := range lioss.RegisteredAlgorithms() {
+		lines = append(lines, fmt.Sprintf("    %-30s %s", entry.Name, entry.Description))
+	}
+	return strings.Join(lines, "\n")
 }
 
 func printErrors(err error, status int) int {
@@ -54,40 +110,149 @@ func printErrors(err error, status int) int {
 	return status
 }
 
-func extractKeys(rm map[lioss.LicenseFile][]*lioss.Result) []lioss.LicenseFile {
-	slice := []lioss.LicenseFile{}
-	for k, _ := range rm {
-		slice = append(slice, k)
+/*
+target shows a project given from the command line arguments.
+err is not nil if the project cannot be opened.
+*/
+type target struct {
+	project lioss.Project
+	err     error
+}
+
+/*
+stdin is the reader of the license text given by "-" argument.
+*/
+var stdin io.Reader = os.Stdin
+
+func openTargets(args []string) []*target {
+	targets := []*target{}
+	for _, arg := range args {
+		project, err := openProject(arg)
+		targets = append(targets, &target{project: project, err: err})
 	}
-	sort.Slice(slice, func(i, j int) bool {
-		return slice[i].ID() < slice[j].ID()
-	})
-	return slice
+	return targets
 }
 
-func printResults(identifier *lioss.Identifier, project lioss.Project) {
-	resultMap, err := identifier.Identify(project)
+func openProject(arg string) (lioss.Project, error) {
+	if arg != "-" {
+		return lioss.NewProject(arg)
+	}
+	data, err := ioutil.ReadAll(stdin)
 	if err != nil {
-		fmt.Printf(`%s: %s\n`, project.BasePath(), err.Error())
-		return
+		return nil, fmt.Errorf("-: %s", err.Error())
+	}
+	return lioss.NewTextProject("-", string(data)), nil
+}
+
+func closeTargets(targets []*target) {
+	for _, target := range targets {
+		if target.project != nil {
+			target.project.Close()
+		}
 	}
-	keys := extractKeys(resultMap)
-	for _, key := range keys {
-		printResult(project, key.ID(), resultMap[key])
+}
+
+func openedProjects(targets []*target) []lioss.Project {
+	projects := []lioss.Project{}
+	for _, target := range targets {
+		if target.err == nil {
+			projects = append(projects, target.project)
+		}
 	}
+	return projects
 }
 
-func performEach(identifier *lioss.Identifier, arg string, opts *liossOptions) {
-	project, err := lioss.NewProject(arg)
+func performAll(identifier *lioss.Identifier, args []string, opts *liossOptions, policy *lioss.Policy) int {
+	printer, err := newPrinter(os.Stdout, identifier, opts)
 	if err != nil {
-		fmt.Printf("%s\n", err.Error())
-		return
+		return printErrors(err, 2)
+	}
+	targets := openTargets(args)
+	defer closeTargets(targets)
+	results, err := identifyAll(identifier, openedProjects(targets), opts)
+	if err != nil {
+		return printErrors(err, errorStatus)
+	}
+	verdict := lioss.Allowed
+	failed := false
+	index := 0
+	for _, target := range targets {
+		if target.err != nil {
+			printer.PrintError(target.err)
+			failed = true
+			continue
+		}
+		violations := evaluatePolicy(policy, results[index])
+		printer.PrintProject(results[index], violations)
+		verdict = severer(verdict, violations)
+		failed = failed || results[index].Err != nil
+		index++
+	}
+	if err := printer.Close(); err != nil {
+		return printErrors(err, errorStatus)
+	}
+	if failed {
+		return errorStatus
+	}
+	return statusOf(verdict)
+}
+
+/*
+identifyAll identifies the given projects, with rendering the progress into stderr in the progress mode.
+The results are not printed if the identification is aborted, since the results of the projects are incomplete.
+*/
+func identifyAll(identifier *lioss.Identifier, projects []lioss.Project, opts *liossOptions) ([]*lioss.ProjectResult, error) {
+	if !opts.progress {
+		return identifier.IdentifyAll(context.Background(), projects, opts.jobs)
 	}
-	defer project.Close()
-	printResults(identifier, project)
-	if len(project.LicenseIDs()) == 0 {
-		fmt.Printf("%s: license file not found\n", project.BasePath())
+	bar := newProgressBar(os.Stderr, len(projects))
+	identifier.Observer = bar
+	defer bar.Close()
+	return identifier.IdentifyAll(context.Background(), projects, opts.jobs)
+}
+
+/*
+reviewRequiredStatus and deniedStatus are the exit statuses when the results violate the policy.
+errorStatus is the exit status when some projects cannot be opened or identified, and it takes precedence over the policy verdict,
+since the violations of the failed projects are unknown.
+*/
+const (
+	reviewRequiredStatus = 3
+	deniedStatus         = 4
+	errorStatus          = 5
+)
+
+func readPolicy(opts *liossOptions) (*lioss.Policy, error) {
+	if opts.policy == "" {
+		return nil, nil
+	}
+	return lioss.ReadPolicy(opts.policy)
+}
+
+func evaluatePolicy(policy *lioss.Policy, result *lioss.ProjectResult) []*lioss.Violation {
+	if policy == nil || result.Err != nil {
+		return []*lioss.Violation{}
 	}
+	return policy.Evaluate(result)
+}
+
+func severer(verdict lioss.Verdict, violations []*lioss.Violation) lioss.Verdict {
+	for _, violation := range violations {
+		if violation.Verdict > verdict {
+			verdict = violation.Verdict
+		}
+	}
+	return verdict
+}
+
+func statusOf(verdict lioss.Verdict) int {
+	switch verdict {
+	case lioss.ReviewRequired:
+		return reviewRequiredStatus
+	case lioss.Denied:
+		return deniedStatus
+	}
+	return 0
 }
 
 func dbTypes(opts *liossOptions) lioss.DatabaseType {
@@ -125,20 +290,34 @@ func loadDatabase(opts *liossOptions) (*lioss.Database, error) {
 	return lioss.ReadDatabase(opts.dbPath)
 }
 
-func perform(args []string, opts *liossOptions) int {
+func buildIdentifier(opts *liossOptions) (*lioss.Identifier, int, error) {
 	db, err := loadDatabase(opts)
-
 	if err != nil {
-		return printErrors(err, 1)
+		return nil, 1, err
 	}
 	identifier, err := lioss.NewIdentifier(opts.algorithm, opts.threshold, db)
+	if err != nil {
+		return nil, 2, err
+	}
+	if !opts.thresholdFlag {
+		identifier.Threshold = db.DefaultThreshold(identifier.Comparator.String())
+	}
+	identifier.Exhaustive = opts.exhaustive
+	identifier.TopN = opts.top
+	identifier.MinMargin = opts.minMargin
+	return identifier, 0, nil
+}
+
+func perform(args []string, opts *liossOptions) int {
+	policy, err := readPolicy(opts)
 	if err != nil {
 		return printErrors(err, 2)
 	}
-	for _, arg := range args {
-		performEach(identifier, arg, opts)
+	identifier, status, err := buildIdentifier(opts)
+	if err != nil {
+		return printErrors(err, status)
 	}
-	return 0
+	return performAll(identifier, args, opts, policy)
 }
 
 func buildFlagSet() (*flag.FlagSet, *liossOptions) {
@@ -150,6 +329,15 @@ func buildFlagSet() (*flag.FlagSet, *liossOptions) {
 	flags.StringVarP(&opts.dbtype, "database-type", "d", "osi", "specifies the database type")
 	flags.StringVarP(&opts.dbPath, "database-path", "p", "", "specifies the database path")
 	flags.Float64VarP(&opts.threshold, "threshold", "t", 0.75, "specifies threshold")
+	flags.IntVarP(&opts.jobs, "jobs", "j", runtime.NumCPU(), "specifies the number of jobs")
+	flags.BoolVar(&opts.exhaustive, "exhaustive", false, "compares with all of licenses")
+	flags.IntVarP(&opts.top, "top", "n", 0, "prints the top N results")
+	flags.Float64Var(&opts.minMargin, "min-margin", 0.0, "specifies the minimum margin")
+	flags.BoolVarP(&opts.copyright, "copyright", "c", false, "prints the copyright notices")
+	flags.StringVar(&opts.policy, "policy", "", "specifies the policy file")
+	flags.StringVarP(&opts.format, "format", "f", formats[0], "specifies the output format")
+	flags.StringVar(&opts.template, "template", "", "specifies the template file")
+	flags.BoolVar(&opts.progress, "progress", false, "prints the progress")
 	return flags, opts
 }
 
@@ -163,6 +351,13 @@ func parseOptions(args []string, flags *flag.FlagSet, opts *liossOptions) (int,
 	if err := validateOptions(opts, flags.Args()[1:]); err != nil {
 		return 2, err
 	}
+	if err := isValidFormat(opts); err != nil {
+		return 2, err
+	}
+	if opts.template != "" && flags.Changed("format") {
+		return 2, fmt.Errorf("format and template options cannot be specified together")
+	}
+	opts.thresholdFlag = flags.Changed("threshold")
 	return 0, nil
 }
 
@@ -171,6 +366,15 @@ func (opts *liossOptions) isHelpFlag() bool {
 }
 
 func goMain(args []string) int {
+	if len(args) > 1 && args[1] == "serve" {
+		return goServe(args[1:])
+	}
+	if len(args) > 1 && args[1] == "notice" {
+		return goNotice(args[1:])
+	}
+	if len(args) > 1 && args[1] == "report" {
+		return goReport(args[1:])
+	}
 	flags, opts := buildFlagSet()
 	status, err := parseOptions(args, flags, opts)
 	if err != nil {
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/tamada/lioss"
@@ -17,9 +20,15 @@ func TestInvalidOptions(t *testing.T) {
 		{[]string{"lioss"}, true, 2, "no arguments"},
 		{[]string{"lioss", "--algorithm", "tfidf"}, true, 2, "no arguments"},
 		{[]string{"lioss", "-a", "unknown"}, true, 2, "unknown: unknown algorithm"},
+		{[]string{"lioss", "-a", "10gram", "../../LICENSE"}, true, 2, "10gram: unknown algorithm"},
 		{[]string{"lioss", "-t", "2.0"}, true, 2, "2.000000: threshold must be 0.0 to 1.0"},
 		{[]string{"lioss", "--database-path", "no/such/file", "../../LICENSE"}, true, 2, "no/such/file: file not found"},
 		{[]string{"lioss", "--database-type", "unknown", "../../LICENSE"}, true, 2, "unknown: invalid database type"},
+		{[]string{"lioss", "--jobs", "0", "../../LICENSE"}, true, 2, "0: jobs must be positive"},
+		{[]string{"lioss", "--top", "-1", "../../LICENSE"}, true, 2, "-1: top must not be negative"},
+		{[]string{"lioss", "--min-margin", "1.5", "../../LICENSE"}, true, 2, "1.500000: min-margin must be 0.0 to 1.0"},
+		{[]string{"lioss", "--policy", "no/such/policy.json", "../../LICENSE"}, true, 2, "no/such/policy.json: file not found"},
+		{[]string{"lioss", "--format", "xml", "../../LICENSE"}, true, 2, "xml: unknown format"},
 	}
 
 	for _, td := range testdata {
@@ -37,6 +46,49 @@ func TestInvalidOptions(t *testing.T) {
 	}
 }
 
+func TestPolicyStatus(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "lioss")
+	defer os.RemoveAll(dir)
+	testdata := []struct {
+		givePolicy string
+		wontStatus int
+	}{
+		{`{"allow": ["Apache-*", "BSD"], "no-license": "allow"}`, 0},
+		{`{"allow": ["Apache-*"], "review": ["BSD"], "no-license": "allow"}`, 3},
+		{`{"allow": ["Apache-*", "BSD"]}`, 4},
+		{`{"allow": "Apache-*"}`, 2},
+	}
+	for i, td := range testdata {
+		path := filepath.Join(dir, fmt.Sprintf("policy%d.json", i))
+		ioutil.WriteFile(path, []byte(td.givePolicy), 0644)
+		status := goMain([]string{"lioss", "--database-path", "../../testdata/test.liossgz", "--algorithm", "6gram", "--policy", path, "../../testdata/project3.jar", "../../testdata/project4"})
+		if status != td.wontStatus {
+			t.Errorf("status with policy %s did not match, wont %d, got %d", td.givePolicy, td.wontStatus, status)
+		}
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "lioss")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "policy.json")
+	ioutil.WriteFile(path, []byte(`{"allow": ["Apache-*", "BSD"], "no-license": "allow"}`), 0644)
+	testdata := []struct {
+		args       []string
+		wontStatus int
+	}{
+		{[]string{"../../testdata/project3.jar", "../../testdata/no/such/project"}, 5},
+		{[]string{"--policy", path, "../../testdata/no/such/project"}, 5},
+		{[]string{"--policy", path, "../../testdata/project3.jar"}, 0},
+	}
+	for _, td := range testdata {
+		args := append([]string{"lioss", "--database-path", "../../testdata/test.liossgz", "--algorithm", "6gram"}, td.args...)
+		if status := goMain(args); status != td.wontStatus {
+			t.Errorf("status of %v did not match, wont %d, got %d", td.args, td.wontStatus, status)
+		}
+	}
+}
+
 func TestDatabaseType(t *testing.T) {
 	testdata := []struct {
 		types    string
@@ -116,16 +168,60 @@ func Example_printHelp() {
 	// Output:
 	// lioss version 1.0.0
 	// lioss [OPTIONS] <PROJECTS...>
+	// lioss serve [SERVE_OPTIONS]
+	// lioss notice [NOTICE_OPTIONS] <PROJECTS...>
+	// lioss report [REPORT_OPTIONS] --html <DIR> <PROJECTS...>
 	// OPTIONS
 	//         --database-path <PATH>     specifies the database path.
 	//                                    If specifying this option, database-type option is ignored.
 	//         --database-type <TYPE>     specifies the database type. Default is osi.
 	//                                    Available values are: non-osi, osi, deprecated, osi-deprecated, and whole.
 	//     -a, --algorithm <ALGORITHM>    specifies algorithm. Default is 5gram.
-	//                                    Available values are: kgram, wordfreq, and tfidf.
+	//                                    Available values are: kgram, wordfreq, tfidf, kshingle, kshingle-containment, bm25, and ensemble.
 	//     -t, --threshold <THRESHOLD>    specifies threshold of the similarities of license files.
-	//                                    Each algorithm has default value. Default value is 0.75.
+	//                                    Default is the calibrated threshold in the database,
+	//                                    or the default value of each algorithm (0.75).
+	//     -j, --jobs <JOBS>              specifies the number of projects identified in parallel.
+	//                                    Default is the number of CPUs.
+	//         --exhaustive               compares with all of licenses in the database without the MinHash index.
+	//     -n, --top <N>                  prints the top N results of each license file. Default is 0 (all results).
+	//         --min-margin <MARGIN>      flags the license file as ambiguous if the margin of probabilities between
+	//                                    the first and the second results is less than MARGIN. Default is 0.0 (disabled).
+	//     -c, --copyright                prints the copyright notices found in the license files.
+	//         --policy <FILE>            evaluates the results by the given policy file (JSON), and prints the violations.
+	//                                    The exit status is 3 if some licenses require the review, and 4 if denied.
+	//     -f, --format <FORMAT>          specifies the output format. Default is text.
+	//                                    Available values are: text, sarif, csv, and markdown.
+	//         --template <FILE>          prints the results by the given Go text/template file, instead of the format.
+	//         --progress                 prints the progress of the identification into stderr.
 	//     -h, --help                     prints this message.
 	// PROJECTS
 	//     project directories, and/or archive files contains LICENSE file.
+	//     "-" reads the license text from stdin.
+	//     The exit status is 5 if some projects cannot be opened or identified.
+	// ALGORITHMS
+	//     kgram                          compares the frequencies of k characters (k=1, ..., 9) by cosine similarity.
+	//     wordfreq                       compares the frequencies of words by cosine similarity.
+	//     tfidf                          compares the tf-idf of words by cosine similarity.
+	//     kshingle                       compares the sequences of k words (k=1, ..., 9) by Jaccard similarity.
+	//     kshingle-containment           scores how much of the sequences of k words of the license are contained.
+	//     bm25                           ranks by Okapi BM25 (parameters by bm25:k1=<K1>,b=<B>).
+	//     ensemble                       combines the given algorithms (e.g., ensemble:5gram,tfidf).
+	// SERVE_OPTIONS
+	//     see "lioss serve --help".
+	// NOTICE_OPTIONS
+	//     see "lioss notice --help".
+	// REPORT_OPTIONS
+	//     see "lioss report --help".
+}
+
+func Example_stdin() {
+	file, _ := os.Open("../../data/misc/MIT")
+	defer file.Close()
+	stdin = file
+	defer func() { stdin = os.Stdin }()
+	goMain([]string{"lioss", "--database-path", "../../testdata/test.liossgz", "-"})
+	// Output:
+	// -/LICENSE
+	// 	MIT (1.0000)
 }
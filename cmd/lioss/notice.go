@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"github.com/tamada/lioss"
+)
+
+type noticeOptions struct {
+	liossOptions
+	format string
+	output string
+}
+
+func noticeHelpMessage(appName string) string {
+	app := filepath.Base(appName)
+	return fmt.Sprintf(`%s notice [OPTIONS] <PROJECTS...>
+OPTIONS
+        --database-path <PATH>     specifies the database path.
+        --database-type <TYPE>     specifies the database type. Default is osi.
+    -a, --algorithm <ALGORITHM>    specifies algorithm. Default is 5gram.
+    -t, --threshold <THRESHOLD>    specifies threshold of the similarities of license files.
+                                   Default is the calibrated threshold in the database, or 0.75.
+    -j, --jobs <JOBS>              specifies the number of projects identified in parallel.
+    -f, --format <FORMAT>          specifies the format of the notice. Default is text.
+                                   Available values are: text, markdown, and html.
+    -o, --output <FILE>            specifies the destination file. Default is stdout.
+    -h, --help                     prints this message.
+PROJECTS
+    project directories, and/or archive files contains LICENSE file.
+    "-" reads the license text from stdin.
+    The NOTICE files in the projects are also included in the resultant notice.`, app)
+}
+
+func buildNoticeFlagSet() (*flag.FlagSet, *noticeOptions) {
+	var opts = new(noticeOptions)
+	var flags = flag.NewFlagSet("notice", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Println(noticeHelpMessage("lioss")) }
+	flags.BoolVarP(&opts.helpFlag, "help", "h", false, "print this message")
+	flags.StringVarP(&opts.algorithm, "algorithm", "a", "5gram", "specifies algorithm")
+	flags.StringVarP(&opts.dbtype, "database-type", "d", "osi", "specifies the database type")
+	flags.StringVarP(&opts.dbPath, "database-path", "p", "", "specifies the database path")
+	flags.Float64VarP(&opts.threshold, "threshold", "t", 0.75, "specifies threshold")
+	flags.IntVarP(&opts.jobs, "jobs", "j", runtime.NumCPU(), "specifies the number of jobs")
+	flags.StringVarP(&opts.format, "format", "f", "text", "specifies the format of the notice")
+	flags.StringVarP(&opts.output, "output", "o", "", "specifies the destination file")
+	return flags, opts
+}
+
+func isValidNoticeFormat(format string) error {
+	if contains(strings.ToLower(format), lioss.NoticeFormats()) {
+		return nil
+	}
+	return fmt.Errorf("%s: unknown notice format", format)
+}
+
+func parseNoticeOptions(args []string) (*noticeOptions, []string, int, error) {
+	flags, opts := buildNoticeFlagSet()
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, 1, err
+	}
+	if opts.isHelpFlag() {
+		return nil, nil, 0, fmt.Errorf("%s", noticeHelpMessage("lioss"))
+	}
+	if err := validateOptions(&opts.liossOptions, flags.Args()[1:]); err != nil {
+		return nil, nil, 2, err
+	}
+	if err := isValidNoticeFormat(opts.format); err != nil {
+		return nil, nil, 2, err
+	}
+	opts.thresholdFlag = flags.Changed("threshold")
+	return opts, flags.Args()[1:], 0, nil
+}
+
+func goNotice(args []string) int {
+	opts, projects, status, err := parseNoticeOptions(args)
+	if err != nil {
+		fmt.Println(err.Error())
+		return status
+	}
+	identifier, status, err := buildIdentifier(&opts.liossOptions)
+	if err != nil {
+		return printErrors(err, status)
+	}
+	return performNotice(identifier, projects, opts)
+}
+
+func performNotice(identifier *lioss.Identifier, args []string, opts *noticeOptions) int {
+	targets := openTargets(args)
+	defer closeTargets(targets)
+	for _, target := range targets {
+		if target.err != nil {
+			return printErrors(target.err, 3)
+		}
+	}
+	results, err := identifier.IdentifyAll(context.Background(), openedProjects(targets), opts.jobs)
+	if err != nil {
+		return printErrors(err, 3)
+	}
+	notice, err := lioss.BuildNotice(results)
+	if err != nil {
+		return printErrors(err, 3)
+	}
+	if err := writeNotice(notice, opts); err != nil {
+		return printErrors(err, 4)
+	}
+	return 0
+}
+
+func writeNotice(notice *lioss.Notice, opts *noticeOptions) error {
+	if opts.output == "" {
+		return notice.Write(os.Stdout, opts.format)
+	}
+	file, err := os.Create(opts.output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return notice.Write(file, opts.format)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNotice(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "lioss")
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "NOTICE.md")
+	status := goMain([]string{"lioss", "notice", "--database-path", "../../testdata/test.liossgz", "--format", "markdown", "--output", output, "../../testdata/project3", "../../testdata/project1"})
+	if status != 0 {
+		t.Fatalf("notice failed: status %d", status)
+	}
+	data, _ := ioutil.ReadFile(output)
+	for _, wont := range []string{"## Apache-License-2.0", "### project3", "The Project3 Authors", "## WTFPL"} {
+		if !strings.Contains(string(data), wont) {
+			t.Errorf("notice did not contain %s", wont)
+		}
+	}
+}
+
+func TestInvalidNoticeOptions(t *testing.T) {
+	testdata := []struct {
+		args       []string
+		wontStatus int
+	}{
+		{[]string{"lioss", "notice", "--format", "pdf", "../../testdata/project1"}, 2},
+		{[]string{"lioss", "notice"}, 2},
+		{[]string{"lioss", "notice", "--unknown"}, 1},
+		{[]string{"lioss", "notice", "--database-path", "../../testdata/test.liossgz", "not-exist"}, 3},
+	}
+	for _, td := range testdata {
+		if status := goMain(td.args); status != td.wontStatus {
+			t.Errorf("%v: status did not match, wont %d, got %d", td.args, td.wontStatus, status)
+		}
+	}
+}
+
+func Example_noticeHelp() {
+	goMain([]string{"lioss", "notice", "--help"})
+	// Output:
+	// lioss notice [OPTIONS] <PROJECTS...>
+	// OPTIONS
+	//         --database-path <PATH>     specifies the database path.
+	//         --database-type <TYPE>     specifies the database type. Default is osi.
+	//     -a, --algorithm <ALGORITHM>    specifies algorithm. Default is 5gram.
+	//     -t, --threshold <THRESHOLD>    specifies threshold of the similarities of license files.
+	//                                    Default is the calibrated threshold in the database, or 0.75.
+	//     -j, --jobs <JOBS>              specifies the number of projects identified in parallel.
+	//     -f, --format <FORMAT>          specifies the format of the notice. Default is text.
+	//                                    Available values are: text, markdown, and html.
+	//     -o, --output <FILE>            specifies the destination file. Default is stdout.
+	//     -h, --help                     prints this message.
+	// PROJECTS
+	//     project directories, and/or archive files contains LICENSE file.
+	//     "-" reads the license text from stdin.
+	//     The NOTICE files in the projects are also included in the resultant notice.
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tamada/lioss"
+	"github.com/tamada/lioss/lib"
+)
+
+/*
+resultPrinter prints the identified results of projects in a format.
+PrintError prints the error of the project which cannot be opened,
+PrintProject prints the result of a project with the policy violations of it,
+and Close flushes the results, if the printer buffers them.
+*/
+type resultPrinter interface {
+	PrintError(err error)
+	PrintProject(result *lioss.ProjectResult, violations []*lioss.Violation)
+	Close() error
+}
+
+/*
+formats shows the available values of format option, and the first one is the default.
+*/
+var formats = []string{"text", "sarif", "csv", "markdown"}
+
+/*
+newPrinter returns the printer of the given format, or the template printer if template option is given.
+*/
+func newPrinter(writer io.Writer, identifier *lioss.Identifier, opts *liossOptions) (resultPrinter, error) {
+	if opts.template != "" {
+		return newTemplatePrinter(writer, identifier, opts)
+	}
+	switch strings.ToLower(opts.format) {
+	case "sarif":
+		return newSarifPrinter(writer, identifier), nil
+	case "csv":
+		return newTablePrinter(&csvWriter{writer: csv.NewWriter(writer)}, os.Stderr, identifier), nil
+	case "markdown":
+		return newTablePrinter(&markdownWriter{writer: writer}, os.Stderr, identifier), nil
+	}
+	return &textPrinter{writer: writer, copyright: opts.copyright, violations: []*lioss.Violation{}}, nil
+}
+
+/*
+textPrinter prints the results in the human-readable format, and prints the policy violations at last.
+*/
+type textPrinter struct {
+	writer     io.Writer
+	copyright  bool
+	violations []*lioss.Violation
+}
+
+func (tp *textPrinter) PrintError(err error) {
+	fmt.Fprintf(tp.writer, "%s\n", err.Error())
+}
+
+func (tp *textPrinter) PrintProject(result *lioss.ProjectResult, violations []*lioss.Violation) {
+	project := result.Project
+	tp.violations = append(tp.violations, violations...)
+	if result.Err != nil {
+		fmt.Fprintf(tp.writer, "%s: %s\n", project.BasePath(), result.Err.Error())
+		return
+	}
+	for _, file := range result.Files {
+		tp.printResult(project, file.File.ID(), file.Results)
+		tp.printAmbiguity(file.Ambiguity)
+		if tp.copyright {
+			tp.printCopyrights(file.Copyrights)
+		}
+	}
+	if len(project.LicenseIDs()) == 0 {
+		fmt.Fprintf(tp.writer, "%s: license file not found\n", project.BasePath())
+	}
+}
+
+func (tp *textPrinter) Close() error {
+	for _, violation := range tp.violations {
+		fmt.Fprintf(tp.writer, "policy: %s\n", violation)
+	}
+	return nil
+}
+
+func (tp *textPrinter) printResult(project lioss.Project, id string, results []*lioss.Result) {
+	fmt.Fprintf(tp.writer, "%s/%s\n", project.BasePath(), id)
+	for i, result := range results {
+		fmt.Fprintf(tp.writer, "\t%s (%1.4f)%s%s\n", result.Expression(), result.Probability, breakdownString(result.Breakdown), grantString(i, result.Grant))
+	}
+}
+
+/*
+grantString returns the mark of the most probable result, if it is a GNU license and its version grant is undetermined.
+*/
+func grantString(index int, grant *lioss.Grant) string {
+	if index > 0 || grant == nil || grant.Status != lioss.GrantUndetermined {
+		return ""
+	}
+	return " [version grant undetermined]"
+}
+
+func (tp *textPrinter) printAmbiguity(ambiguity *lioss.Ambiguity) {
+	if ambiguity == nil {
+		return
+	}
+	fmt.Fprintf(tp.writer, "\tambiguous: the margin between %s is %1.4f\n", strings.Join(ambiguity.Candidates, " and "), ambiguity.Margin)
+	for _, clause := range ambiguity.Clauses {
+		fmt.Fprintf(tp.writer, "\t\t%s (%s): \"%s\"\n", clause.License, foundString(clause.Found), clause.Text)
+	}
+}
+
+func foundString(found bool) string {
+	if found {
+		return "found"
+	}
+	return "not found"
+}
+
+func (tp *textPrinter) printCopyrights(copyrights []*lib.Copyright) {
+	for _, copyright := range copyrights {
+		fmt.Fprintf(tp.writer, "\tcopyright: %s%s\n", copyright.Holder, yearsString(copyright.Years))
+	}
+}
+
+func yearsString(years []string) string {
+	if len(years) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", strings.Join(years, ", "))
+}
+
+func breakdownString(breakdown map[string]float64) string {
+	if len(breakdown) == 0 {
+		return ""
+	}
+	names := []string{}
+	for name := range breakdown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	items := []string{}
+	for _, name := range names {
+		items = append(items, fmt.Sprintf("%s: %1.4f", name, breakdown[name]))
+	}
+	return fmt.Sprintf(" [%s]", strings.Join(items, ", "))
+}
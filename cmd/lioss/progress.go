@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/tamada/lioss"
+)
+
+/*
+progressWidth is the width of the bar of progressBar.
+*/
+const progressWidth = 30
+
+/*
+progressBar is an instance of lioss.Observer, which renders the progress of the identification into writer (stderr).
+The bar shows the ratio of the identified projects, and the counts show the projects, the license files, and the errors.
+*/
+type progressBar struct {
+	writer     io.Writer
+	mutex      sync.Mutex
+	projects   int
+	identified int
+	files      int
+	done       int
+	errors     int
+}
+
+func newProgressBar(writer io.Writer, projects int) *progressBar {
+	bar := &progressBar{writer: writer, projects: projects}
+	bar.render()
+	return bar
+}
+
+/*
+Notify updates the counts by the given event, and renders the progress.
+*/
+func (bar *progressBar) Notify(event *lioss.Event) {
+	bar.mutex.Lock()
+	defer bar.mutex.Unlock()
+	switch event.Type {
+	case lioss.LicenseFileFound:
+		bar.files++
+	case lioss.FileIdentified:
+		bar.done++
+	case lioss.ProjectIdentified:
+		bar.identified++
+	case lioss.ErrorOccurred:
+		bar.errors++
+	}
+	bar.render()
+}
+
+func (bar *progressBar) render() {
+	fmt.Fprintf(bar.writer, "\r%s %d/%d projects, %d/%d files", bar.bar(), bar.identified, bar.projects, bar.done, bar.files)
+	if bar.errors > 0 {
+		fmt.Fprintf(bar.writer, ", %d errors", bar.errors)
+	}
+}
+
+func (bar *progressBar) bar() string {
+	filled := progressWidth
+	if bar.projects > 0 {
+		filled = progressWidth * bar.identified / bar.projects
+	}
+	return fmt.Sprintf("[%s%s]", strings.Repeat("#", filled), strings.Repeat(" ", progressWidth-filled))
+}
+
+/*
+Close finishes the line of the progress, for printing the results after it.
+*/
+func (bar *progressBar) Close() error {
+	bar.mutex.Lock()
+	defer bar.mutex.Unlock()
+	_, err := fmt.Fprintln(bar.writer)
+	return err
+}
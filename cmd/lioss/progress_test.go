@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tamada/lioss"
+)
+
+func TestProgressBar(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	bar := newProgressBar(buffer, 2)
+	events := []lioss.EventType{lioss.ProjectOpened, lioss.LicenseFileFound, lioss.LicenseFileFound, lioss.FileIdentified, lioss.ProjectIdentified}
+	for _, eventType := range events {
+		bar.Notify(&lioss.Event{Type: eventType})
+	}
+	bar.Notify(&lioss.Event{Type: lioss.ErrorOccurred, Err: errors.New("error")})
+	bar.Close()
+	wont := "\r[###############               ] 1/2 projects, 1/2 files, 1 errors\n"
+	if got := buffer.String(); !strings.HasSuffix(got, wont) {
+		t.Errorf("last progress did not match, wont %q, got %q", wont, got)
+	}
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"github.com/tamada/lioss"
+	"github.com/tamada/lioss/lib"
+)
+
+type reportOptions struct {
+	liossOptions
+	html             string
+	templates        string
+	displayThreshold float64
+}
+
+func reportHelpMessage(appName string) string {
+	app := filepath.Base(appName)
+	return fmt.Sprintf(`%s report [OPTIONS] --html <DIR> <PROJECTS...>
+OPTIONS
+        --database-path <PATH>     specifies the database path.
+        --database-type <TYPE>     specifies the database type. Default is osi.
+    -a, --algorithm <ALGORITHM>    specifies algorithm. Default is 5gram.
+    -t, --threshold <THRESHOLD>    specifies threshold of the similarities of license files.
+                                   Default is the calibrated threshold in the database, or 0.75.
+        --display-threshold <THRESHOLD>
+                                   specifies the lower threshold for displaying the candidates. Default is 0.5.
+    -j, --jobs <JOBS>              specifies the number of projects identified in parallel.
+        --min-margin <MARGIN>      flags the license file as ambiguous if the margin of probabilities between
+                                   the first and the second candidates is less than MARGIN. Default is 0.0 (disabled).
+        --html <DIR>               specifies the destination directory of the HTML report.
+        --templates <DIR>          specifies the directory containing the templates (index.html, and/or file.html)
+                                   overriding the default ones.
+    -h, --help                     prints this message.
+PROJECTS
+    project directories, and/or archive files contains LICENSE file.
+    "-" reads the license text from stdin.`, app)
+}
+
+func buildReportFlagSet() (*flag.FlagSet, *reportOptions) {
+	var opts = new(reportOptions)
+	var flags = flag.NewFlagSet("report", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Println(reportHelpMessage("lioss")) }
+	flags.BoolVarP(&opts.helpFlag, "help", "h", false, "print this message")
+	flags.StringVarP(&opts.algorithm, "algorithm", "a", "5gram", "specifies algorithm")
+	flags.StringVarP(&opts.dbtype, "database-type", "d", "osi", "specifies the database type")
+	flags.StringVarP(&opts.dbPath, "database-path", "p", "", "specifies the database path")
+	flags.Float64VarP(&opts.threshold, "threshold", "t", 0.75, "specifies threshold")
+	flags.Float64Var(&opts.displayThreshold, "display-threshold", 0.5, "specifies the lower threshold for displaying")
+	flags.IntVarP(&opts.jobs, "jobs", "j", runtime.NumCPU(), "specifies the number of jobs")
+	flags.Float64Var(&opts.minMargin, "min-margin", 0.0, "specifies the minimum margin")
+	flags.StringVar(&opts.html, "html", "", "specifies the destination directory")
+	flags.StringVar(&opts.templates, "templates", "", "specifies the template directory")
+	return flags, opts
+}
+
+func validateReportOptions(opts *reportOptions) error {
+	if opts.html == "" {
+		return fmt.Errorf("html option is required")
+	}
+	if opts.displayThreshold < 0.0 || opts.displayThreshold > 1.0 {
+		return fmt.Errorf("%f: display-threshold must be 0.0 to 1.0", opts.displayThreshold)
+	}
+	if opts.templates != "" && !existsFile(opts.templates) {
+		return fmt.Errorf("%s: directory not found", opts.templates)
+	}
+	return nil
+}
+
+func parseReportOptions(args []string) (*reportOptions, []string, int, error) {
+	flags, opts := buildReportFlagSet()
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, 1, err
+	}
+	if opts.isHelpFlag() {
+		return nil, nil, 0, fmt.Errorf("%s", reportHelpMessage("lioss"))
+	}
+	if err := validateOptions(&opts.liossOptions, flags.Args()[1:]); err != nil {
+		return nil, nil, 2, err
+	}
+	if err := validateReportOptions(opts); err != nil {
+		return nil, nil, 2, err
+	}
+	opts.thresholdFlag = flags.Changed("threshold")
+	return opts, flags.Args()[1:], 0, nil
+}
+
+func goReport(args []string) int {
+	opts, projects, status, err := parseReportOptions(args)
+	if err != nil {
+		fmt.Println(err.Error())
+		return status
+	}
+	identifier, status, err := buildIdentifier(&opts.liossOptions)
+	if err != nil {
+		return printErrors(err, status)
+	}
+	templates, err := loadReportTemplates(opts.templates)
+	if err != nil {
+		return printErrors(err, 2)
+	}
+	report, err := performReport(identifier, projects, opts)
+	if err != nil {
+		return printErrors(err, 4)
+	}
+	if err := report.write(opts.html, templates); err != nil {
+		return printErrors(err, 4)
+	}
+	return 0
+}
+
+/*
+report is the data for the templates of the HTML report.
+The candidates of each license file are identified by DisplayThreshold, and the candidates above Threshold are treated as the identified licenses.
+*/
+type report struct {
+	Generated        string
+	Algorithm        string
+	Threshold        float64
+	DisplayThreshold float64
+	Licenses         []*reportCount
+	Categories       []*reportCount
+	Projects         []*reportProject
+	Files            []*reportFile
+}
+
+type reportCount struct {
+	Name  string
+	Count int
+}
+
+type reportProject struct {
+	Path       string
+	Err        string
+	Files      []*reportFile
+	Licenses   []string
+	Categories []string
+}
+
+type reportFile struct {
+	Page        string
+	Project     string
+	ID          string
+	License     string
+	Category    string
+	Probability float64
+	Candidates  []*reportCandidate
+	Copyrights  []*lib.Copyright
+	Ambiguity   *lioss.Ambiguity
+	Text        string
+	Canonical   string
+	Diff        []*lib.DiffOp
+}
+
+type reportCandidate struct {
+	Result     *lioss.Result
+	Identified bool
+}
+
+const unknownCategory = "unknown"
+
+func performReport(identifier *lioss.Identifier, args []string, opts *reportOptions) (*report, error) {
+	threshold := identifier.Threshold
+	if opts.displayThreshold < threshold {
+		identifier.Threshold = opts.displayThreshold
+	}
+	report := &report{Generated: time.Now().Format("2006-01-02 15:04:05"), Algorithm: identifier.Comparator.String(),
+		Threshold: threshold, DisplayThreshold: identifier.Threshold, Projects: []*reportProject{}, Files: []*reportFile{}}
+	targets := openTargets(args)
+	defer closeTargets(targets)
+	results, err := identifier.IdentifyAll(context.Background(), openedProjects(targets), opts.jobs)
+	if err != nil {
+		return nil, err
+	}
+	index := 0
+	for i, target := range targets {
+		if target.err != nil {
+			report.Projects = append(report.Projects, &reportProject{Path: args[i], Err: target.err.Error()})
+			continue
+		}
+		report.appendProject(identifier.Database, results[index], threshold)
+		index++
+	}
+	report.Licenses = countBy(report.Files, func(file *reportFile) string { return file.License })
+	report.Categories = countBy(report.Files, func(file *reportFile) string { return file.Category })
+	return report, nil
+}
+
+func (report *report) appendProject(db *lioss.Database, result *lioss.ProjectResult, threshold float64) {
+	project := &reportProject{Path: result.Project.BasePath(), Files: []*reportFile{}, Licenses: []string{}, Categories: []string{}}
+	report.Projects = append(report.Projects, project)
+	if result.Err != nil {
+		project.Err = result.Err.Error()
+		return
+	}
+	for _, file := range result.Files {
+		reportFile := newReportFile(db, result.Project, file, threshold)
+		reportFile.Page = fmt.Sprintf("files/%d.html", len(report.Files)+1)
+		report.Files = append(report.Files, reportFile)
+		project.Files = append(project.Files, reportFile)
+		project.Licenses = appendUnique(project.Licenses, reportFile.License)
+		project.Categories = appendUnique(project.Categories, reportFile.Category)
+	}
+}
+
+func newReportFile(db *lioss.Database, project lioss.Project, file *lioss.FileResult, threshold float64) *reportFile {
+	reportFile := &reportFile{Project: project.BasePath(), ID: file.File.ID(), License: lioss.UnknownLicense, Category: unknownCategory,
+		Candidates: []*reportCandidate{}, Copyrights: file.Copyrights, Ambiguity: file.Ambiguity, Diff: []*lib.DiffOp{}}
+	for _, result := range file.Results {
+		reportFile.Candidates = append(reportFile.Candidates, &reportCandidate{Result: result, Identified: result.Probability >= threshold})
+	}
+	reportFile.Text = readLicenseText(project, file.File.ID())
+	if len(file.Results) == 0 || file.Results[0].Probability < threshold {
+		return reportFile
+	}
+	top := file.Results[0]
+	reportFile.License = top.Expression()
+	reportFile.Probability = top.Probability
+	if top.Classification != nil {
+		reportFile.Category = string(top.Classification.Category)
+	}
+	if canonical, ok := db.Text(top.Name); ok {
+		reportFile.Canonical = canonical
+		reportFile.Diff = lib.WordDiff(canonical, reportFile.Text)
+	}
+	return reportFile
+}
+
+func readLicenseText(project lioss.Project, id string) string {
+	file, err := project.LicenseFile(id)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func appendUnique(slice []string, item string) []string {
+	if contains(item, slice) {
+		return slice
+	}
+	return append(slice, item)
+}
+
+func countBy(files []*reportFile, key func(file *reportFile) string) []*reportCount {
+	counts := map[string]int{}
+	for _, file := range files {
+		counts[key(file)]++
+	}
+	results := []*reportCount{}
+	for name, count := range counts {
+		results = append(results, &reportCount{Name: name, Count: count})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Count > results[j].Count || (results[i].Count == results[j].Count && results[i].Name < results[j].Name)
+	})
+	return results
+}
+
+var reportFuncs = template.FuncMap{
+	"join": strings.Join,
+	"probability": func(probability float64) string {
+		return fmt.Sprintf("%1.4f", probability)
+	},
+	"diffClass": func(kind lib.DiffKind) string {
+		return []string{"equal", "insert", "delete"}[kind]
+	},
+}
+
+/*
+loadReportTemplates loads the templates of the HTML report.
+The templates in the given directory (index.html, and file.html) override the default ones, and the directory may be empty.
+*/
+func loadReportTemplates(dir string) (map[string]*template.Template, error) {
+	templates := map[string]*template.Template{}
+	for name, text := range defaultReportTemplates {
+		if dir != "" && existsFile(filepath.Join(dir, name)) {
+			data, err := ioutil.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				return nil, err
+			}
+			text = string(data)
+		}
+		tmpl, err := template.New(name).Funcs(reportFuncs).Parse(reportStyleTemplate + text)
+		if err != nil {
+			return nil, err
+		}
+		templates[name] = tmpl
+	}
+	return templates, nil
+}
+
+func (report *report) write(dir string, templates map[string]*template.Template) error {
+	if err := os.MkdirAll(filepath.Join(dir, "files"), 0755); err != nil {
+		return err
+	}
+	if err := writeTemplate(filepath.Join(dir, "index.html"), templates["index.html"], report); err != nil {
+		return err
+	}
+	for _, file := range report.Files {
+		if err := writeTemplate(filepath.Join(dir, file.Page), templates["file.html"], file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTemplate(path string, tmpl *template.Template, data interface{}) error {
+	writer, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+	return tmpl.Execute(writer, data)
+}
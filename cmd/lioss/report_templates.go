@@ -0,0 +1,107 @@
+package main
+
+/*
+reportStyleTemplate defines the "style" template shared by the pages of the HTML report.
+The overriding templates may use it by {{template "style"}}.
+*/
+const reportStyleTemplate = `{{define "style"}}<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: left; vertical-align: top; }
+th { background: #eee; cursor: pointer; }
+pre { white-space: pre-wrap; background: #f8f8f8; padding: 1em; border: 1px solid #ddd; }
+.identified { font-weight: bold; }
+.error { color: #b00; }
+.insert { background: #cfc; text-decoration: none; }
+.delete { background: #fcc; }
+</style>{{end}}`
+
+var defaultReportTemplates = map[string]string{
+	"index.html": `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>lioss report</title>
+{{template "style"}}
+</head>
+<body>
+<h1>lioss report</h1>
+<p>Generated at {{.Generated}} by {{.Algorithm}} (threshold: {{probability .Threshold}}, display threshold: {{probability .DisplayThreshold}}).</p>
+<h2>Licenses</h2>
+<table>
+<tr><th>License</th><th>Files</th></tr>
+{{range .Licenses}}<tr><td>{{.Name}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>
+<h2>Categories</h2>
+<table>
+<tr><th>Category</th><th>Files</th></tr>
+{{range .Categories}}<tr><td>{{.Name}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>
+<h2>Projects</h2>
+<table id="projects">
+<thead><tr><th onclick="sortTable(0)">Project</th><th onclick="sortTable(1)">License files</th><th onclick="sortTable(2)">Licenses</th><th onclick="sortTable(3)">Categories</th></tr></thead>
+<tbody>
+{{range .Projects}}<tr>
+<td>{{.Path}}</td>
+{{if .Err}}<td colspan="3" class="error">{{.Err}}</td>
+{{else}}<td>{{range .Files}}<a href="{{.Page}}">{{.ID}}</a><br>{{else}}license file not found{{end}}</td>
+<td>{{join .Licenses ", "}}</td>
+<td>{{join .Categories ", "}}</td>
+{{end}}</tr>
+{{end}}</tbody>
+</table>
+<script>
+function sortTable(column) {
+  var tbody = document.getElementById("projects").tBodies[0];
+  var rows = Array.prototype.slice.call(tbody.rows);
+  var ascending = tbody.getAttribute("data-column") != column || tbody.getAttribute("data-order") != "asc";
+  rows.sort(function(a, b) {
+    var x = a.cells[column] ? a.cells[column].textContent : "";
+    var y = b.cells[column] ? b.cells[column].textContent : "";
+    return ascending ? x.localeCompare(y) : y.localeCompare(x);
+  });
+  rows.forEach(function(row) { tbody.appendChild(row); });
+  tbody.setAttribute("data-column", column);
+  tbody.setAttribute("data-order", ascending ? "asc" : "desc");
+}
+</script>
+</body>
+</html>
+`,
+	"file.html": `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Project}}/{{.ID}}</title>
+{{template "style"}}
+</head>
+<body>
+<p><a href="../index.html">back to the summary</a></p>
+<h1>{{.Project}}/{{.ID}}</h1>
+<p>License: {{.License}} ({{.Category}})</p>
+{{if .Copyrights}}<ul>
+{{range .Copyrights}}<li>{{.Statement}}</li>
+{{end}}</ul>
+{{end}}<h2>Candidates</h2>
+<table>
+<tr><th>License</th><th>Probability</th><th>Category</th></tr>
+{{range .Candidates}}<tr{{if .Identified}} class="identified"{{end}}><td>{{.Result.Expression}}</td><td>{{probability .Result.Probability}}</td><td>{{with .Result.Classification}}{{.Category}}{{end}}</td></tr>
+{{else}}<tr><td colspan="3">no candidates</td></tr>
+{{end}}</table>
+{{with .Ambiguity}}<h2>Ambiguity</h2>
+<p class="error">The margin between {{join .Candidates " and "}} is {{probability .Margin}}.</p>
+{{if .Clauses}}<table>
+<tr><th>License</th><th>Distinguishing clause</th><th>In the license file</th></tr>
+{{range .Clauses}}<tr><td>{{.License}}</td><td>{{.Text}}</td><td>{{if .Found}}found{{else}}not found{{end}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}{{if .Diff}}<h2>Differences from {{.License}}</h2>
+<p>The words only in the license file are <ins class="insert">inserted</ins>, and those only in the canonical text are <del class="delete">deleted</del>.</p>
+<pre>{{range .Diff}}{{if eq (diffClass .Kind) "insert"}}<ins class="insert">{{.Text}}</ins> {{else if eq (diffClass .Kind) "delete"}}<del class="delete">{{.Text}}</del> {{else}}{{.Text}} {{end}}{{end}}</pre>
+{{end}}{{if .Canonical}}<h2>Canonical text of {{.License}}</h2>
+<pre>{{.Canonical}}</pre>
+{{end}}<h2>License file</h2>
+<pre>{{.Text}}</pre>
+</body>
+</html>
+`,
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tamada/lioss"
+)
+
+func TestReport(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "lioss")
+	defer os.RemoveAll(dir)
+	db, _ := lioss.ReadDatabase("../../testdata/test.liossgz")
+	data, _ := ioutil.ReadFile("../../testdata/project3/license")
+	db.PutText("Apache-License-2.0", strings.Replace(string(data), "Apache", "Apatch", 1))
+	dbPath := filepath.Join(dir, "test.liossdb")
+	db.WriteTo(dbPath)
+	output := filepath.Join(dir, "report")
+
+	status := goMain([]string{"lioss", "report", "--database-path", dbPath, "--algorithm", "6gram", "--html", output, "../../testdata/project3", "../../testdata/project4", "main.go"})
+	if status != 0 {
+		t.Fatalf("report failed: status %d", status)
+	}
+	testdata := []struct {
+		path  string
+		wonts []string
+	}{
+		{"index.html", []string{"Apache-License-2.0", "MIT", "../../testdata/project4", "license file not found", "main.go", "unknown project format", `href="files/1.html"`}},
+		{"files/1.html", []string{"Apache-License-2.0", `<del class="delete">Apatch</del>`, `<ins class="insert">Apache</ins>`, "Canonical text of Apache-License-2.0"}},
+		{"files/2.html", []string{"MIT"}},
+	}
+	for _, td := range testdata {
+		data, err := ioutil.ReadFile(filepath.Join(output, td.path))
+		if err != nil {
+			t.Errorf("%s: not generated: %s", td.path, err.Error())
+			continue
+		}
+		for _, wont := range td.wonts {
+			if !strings.Contains(string(data), wont) {
+				t.Errorf("%s did not contain %s", td.path, wont)
+			}
+		}
+	}
+}
+
+func TestReportTemplates(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "lioss")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte(`{{range .Licenses}}{{.Name}}={{.Count}};{{end}}{{probability .DisplayThreshold}}`), 0644)
+	output := filepath.Join(dir, "report")
+	status := goMain([]string{"lioss", "report", "--database-path", "../../testdata/test.liossgz", "--algorithm", "6gram", "--templates", dir, "--html", output, "../../testdata/project3"})
+	if status != 0 {
+		t.Fatalf("report failed: status %d", status)
+	}
+	data, _ := ioutil.ReadFile(filepath.Join(output, "index.html"))
+	if wont := "Apache-License-2.0=1;MIT=1;0.5000"; string(data) != wont {
+		t.Errorf("index.html did not match, wont %s, got %s", wont, string(data))
+	}
+	if _, err := os.Stat(filepath.Join(output, "files/1.html")); err != nil {
+		t.Errorf("files/1.html was not generated by the default template")
+	}
+}
+
+func TestInvalidReportOptions(t *testing.T) {
+	testdata := []struct {
+		args       []string
+		wontStatus int
+	}{
+		{[]string{"lioss", "report", "../../testdata/project1"}, 2},
+		{[]string{"lioss", "report", "--html", "out", "--display-threshold", "1.5", "../../testdata/project1"}, 2},
+		{[]string{"lioss", "report", "--html", "out", "--templates", "no/such/dir", "../../testdata/project1"}, 2},
+		{[]string{"lioss", "report", "--html", "out"}, 2},
+		{[]string{"lioss", "report", "--unknown"}, 1},
+	}
+	for _, td := range testdata {
+		if status := goMain(td.args); status != td.wontStatus {
+			t.Errorf("%v: status did not match, wont %d, got %d", td.args, td.wontStatus, status)
+		}
+	}
+}
+
+func Example_reportHelp() {
+	goMain([]string{"lioss", "report", "--help"})
+	// Output:
+	// lioss report [OPTIONS] --html <DIR> <PROJECTS...>
+	// OPTIONS
+	//         --database-path <PATH>     specifies the database path.
+	//         --database-type <TYPE>     specifies the database type. Default is osi.
+	//     -a, --algorithm <ALGORITHM>    specifies algorithm. Default is 5gram.
+	//     -t, --threshold <THRESHOLD>    specifies threshold of the similarities of license files.
+	//                                    Default is the calibrated threshold in the database, or 0.75.
+	//         --display-threshold <THRESHOLD>
+	//                                    specifies the lower threshold for displaying the candidates. Default is 0.5.
+	//     -j, --jobs <JOBS>              specifies the number of projects identified in parallel.
+	//         --min-margin <MARGIN>      flags the license file as ambiguous if the margin of probabilities between
+	//                                    the first and the second candidates is less than MARGIN. Default is 0.0 (disabled).
+	//         --html <DIR>               specifies the destination directory of the HTML report.
+	//         --templates <DIR>          specifies the directory containing the templates (index.html, and/or file.html)
+	//                                    overriding the default ones.
+	//     -h, --help                     prints this message.
+	// PROJECTS
+	//     project directories, and/or archive files contains LICENSE file.
+	//     "-" reads the license text from stdin.
+}
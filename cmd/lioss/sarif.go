@@ -0,0 +1,317 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tamada/lioss"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+/*
+lowConfidenceProbability is the probability below which the most probable license is reported as low-confidence in SARIF.
+*/
+const lowConfidenceProbability = 0.9
+
+/*
+sarifCandidates is the number of the candidate licenses shown in the messages of SARIF results.
+*/
+const sarifCandidates = 3
+
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool          `json:"tool"`
+	Invocations []*sarifInvocation `json:"invocations"`
+	Artifacts   []*sarifArtifact   `json:"artifacts,omitempty"`
+	Results     []*sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	Version        string       `json:"version"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                 `json:"executionSuccessful"`
+	ToolExecutionNotifications []*sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifArtifact struct {
+	Location    sarifArtifactLocation `json:"location"`
+	ParentIndex *int                  `json:"parentIndex,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI   string `json:"uri"`
+	Index *int   `json:"index,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []*sarifLocation       `json:"locations"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+/*
+sarifRules are the rules of SARIF results, and the indexes of them are used as ruleIndex.
+*/
+var sarifRules = []*sarifRule{
+	{ID: "denied-license", ShortDescription: sarifMessage{Text: "The license is denied by the policy."}, DefaultConfiguration: sarifConfiguration{Level: "error"}},
+	{ID: "review-required-license", ShortDescription: sarifMessage{Text: "The license requires the review by the policy."}, DefaultConfiguration: sarifConfiguration{Level: "warning"}},
+	{ID: "unknown-license", ShortDescription: sarifMessage{Text: "No licenses are identified above the threshold."}, DefaultConfiguration: sarifConfiguration{Level: "warning"}},
+	{ID: "no-license-file", ShortDescription: sarifMessage{Text: "The project has no license files."}, DefaultConfiguration: sarifConfiguration{Level: "warning"}},
+	{ID: "ambiguous-license", ShortDescription: sarifMessage{Text: "The identified license is hardly distinguishable from the second candidate."}, DefaultConfiguration: sarifConfiguration{Level: "warning"}},
+	{ID: "low-confidence-license", ShortDescription: sarifMessage{Text: "The probability of the identified license is low."}, DefaultConfiguration: sarifConfiguration{Level: "note"}},
+}
+
+func sarifRuleIndex(id string) int {
+	for i, rule := range sarifRules {
+		if rule.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+/*
+sarifPrinter prints the license files with denied, unknown, or low-confidence licenses as the results of SARIF.
+The licenses in the archives (e.g., jar files) are located by the nested artifacts, whose parents are the archives.
+*/
+type sarifPrinter struct {
+	writer     io.Writer
+	identifier *lioss.Identifier
+	run        *sarifRun
+	artifacts  map[string]int
+}
+
+func newSarifPrinter(writer io.Writer, identifier *lioss.Identifier) *sarifPrinter {
+	run := &sarifRun{
+		Tool:        sarifTool{Driver: sarifDriver{Name: "lioss", Version: VERSION, InformationURI: "https://github.com/tamada/lioss", Rules: sarifRules}},
+		Invocations: []*sarifInvocation{{ExecutionSuccessful: true}},
+		Artifacts:   []*sarifArtifact{},
+		Results:     []*sarifResult{},
+	}
+	return &sarifPrinter{writer: writer, identifier: identifier, run: run, artifacts: map[string]int{}}
+}
+
+func (sp *sarifPrinter) PrintError(err error) {
+	invocation := sp.run.Invocations[0]
+	invocation.ExecutionSuccessful = false
+	invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, &sarifNotification{Level: "error", Message: sarifMessage{Text: err.Error()}})
+}
+
+func (sp *sarifPrinter) PrintProject(result *lioss.ProjectResult, violations []*lioss.Violation) {
+	if result.Err != nil {
+		sp.PrintError(fmt.Errorf("%s: %s", result.Project.BasePath(), result.Err.Error()))
+		return
+	}
+	if len(result.Files) == 0 {
+		violation := findViolation(violations, "")
+		sp.appendResult("no-license-file", levelOf(violation, "warning"), fmt.Sprintf("%s: license file not found", result.Project.BasePath()), sp.projectLocation(result.Project), violationProperties(violation))
+	}
+	for _, file := range result.Files {
+		sp.printFile(result.Project, file, findViolation(violations, file.File.ID()))
+	}
+}
+
+func (sp *sarifPrinter) printFile(project lioss.Project, file *lioss.FileResult, violation *lioss.Violation) {
+	ruleID, level := sarifRuleOf(file, violation)
+	if ruleID == "" {
+		return
+	}
+	message := fmt.Sprintf("%s: %s", sarifRuleMessage(ruleID, violation), candidatesString(file.Results))
+	properties := violationProperties(violation)
+	properties["threshold"] = sp.identifier.Threshold
+	properties["algorithm"] = sp.identifier.Comparator.String()
+	properties["probabilities"] = probabilities(file.Results)
+	if file.Ambiguity != nil {
+		properties["margin"] = file.Ambiguity.Margin
+		properties["clauses"] = file.Ambiguity.Clauses
+	}
+	if len(file.Results) > 0 && file.Results[0].Grant != nil {
+		properties["grant"] = file.Results[0].Grant
+	}
+	if len(file.Results) > 0 && file.Results[0].Classification != nil {
+		properties["category"] = file.Results[0].Classification.Category
+	}
+	sp.appendResult(ruleID, level, message, sp.fileLocation(project, file.File.ID()), properties)
+}
+
+func sarifRuleOf(file *lioss.FileResult, violation *lioss.Violation) (string, string) {
+	results := file.Results
+	switch {
+	case len(results) == 0:
+		return "unknown-license", levelOf(violation, "warning")
+	case violation != nil && violation.Verdict == lioss.Denied:
+		return "denied-license", "error"
+	case violation != nil && violation.Verdict == lioss.ReviewRequired:
+		return "review-required-license", "warning"
+	case file.Ambiguity != nil:
+		return "ambiguous-license", "warning"
+	case results[0].Probability < lowConfidenceProbability:
+		return "low-confidence-license", "note"
+	}
+	return "", ""
+}
+
+func sarifRuleMessage(ruleID string, violation *lioss.Violation) string {
+	message := sarifRules[sarifRuleIndex(ruleID)].ShortDescription.Text
+	if violation == nil {
+		return strings.TrimSuffix(message, ".")
+	}
+	return fmt.Sprintf("%s (%s)", strings.TrimSuffix(message, "."), violation.Rule)
+}
+
+func levelOf(violation *lioss.Violation, defaultLevel string) string {
+	if violation == nil {
+		return defaultLevel
+	}
+	if violation.Verdict == lioss.Denied {
+		return "error"
+	}
+	return "warning"
+}
+
+func findViolation(violations []*lioss.Violation, fileID string) *lioss.Violation {
+	for _, violation := range violations {
+		if violation.File == fileID {
+			return violation
+		}
+	}
+	return nil
+}
+
+func violationProperties(violation *lioss.Violation) map[string]interface{} {
+	properties := map[string]interface{}{}
+	if violation != nil {
+		properties["verdict"] = violation.Verdict.String()
+		properties["policy-rule"] = violation.Rule
+	}
+	return properties
+}
+
+func candidatesString(results []*lioss.Result) string {
+	if len(results) == 0 {
+		return "no candidates"
+	}
+	items := []string{}
+	for i, result := range results {
+		if i >= sarifCandidates {
+			break
+		}
+		items = append(items, fmt.Sprintf("%s (%1.4f)", result.Expression(), result.Probability))
+	}
+	return fmt.Sprintf("candidates: %s", strings.Join(items, ", "))
+}
+
+func probabilities(results []*lioss.Result) map[string]float64 {
+	probabilities := map[string]float64{}
+	for _, result := range results {
+		probabilities[result.Expression()] = result.Probability
+	}
+	return probabilities
+}
+
+func (sp *sarifPrinter) appendResult(ruleID, level, message string, location *sarifLocation, properties map[string]interface{}) {
+	sp.run.Results = append(sp.run.Results, &sarifResult{
+		RuleID: ruleID, RuleIndex: sarifRuleIndex(ruleID), Level: level, Message: sarifMessage{Text: message},
+		Locations: []*sarifLocation{location}, Properties: properties,
+	})
+}
+
+func (sp *sarifPrinter) projectLocation(project lioss.Project) *sarifLocation {
+	uri := filepath.ToSlash(project.BasePath())
+	index := sp.artifactIndex(uri, nil)
+	return &sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri, Index: &index}}}
+}
+
+/*
+fileLocation returns the location of the given license file.
+If the project is an archive, the location refers the entry in the archive, whose parent is the archive artifact.
+*/
+func (sp *sarifPrinter) fileLocation(project lioss.Project, id string) *sarifLocation {
+	if !isArchive(project) {
+		uri := filepath.ToSlash(filepath.Join(project.BasePath(), id))
+		index := sp.artifactIndex(uri, nil)
+		return &sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri, Index: &index}}}
+	}
+	parent := sp.artifactIndex(filepath.ToSlash(project.BasePath()), nil)
+	uri := strings.TrimPrefix(filepath.ToSlash(id), "/")
+	index := sp.artifactIndex(uri, &parent)
+	return &sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri, Index: &index}}}
+}
+
+func isArchive(project lioss.Project) bool {
+	info, err := os.Stat(project.BasePath())
+	return err == nil && !info.IsDir()
+}
+
+func (sp *sarifPrinter) artifactIndex(uri string, parent *int) int {
+	key := uri
+	if parent != nil {
+		key = fmt.Sprintf("%d:%s", *parent, uri)
+	}
+	if index, ok := sp.artifacts[key]; ok {
+		return index
+	}
+	index := len(sp.run.Artifacts)
+	sp.run.Artifacts = append(sp.run.Artifacts, &sarifArtifact{Location: sarifArtifactLocation{URI: uri}, ParentIndex: parent})
+	sp.artifacts[key] = index
+	return index
+}
+
+func (sp *sarifPrinter) Close() error {
+	log := &sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []*sarifRun{sp.run}}
+	bytes, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(sp.writer, string(bytes))
+	return err
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/tamada/lioss"
+)
+
+func TestSarifPrinter(t *testing.T) {
+	db, _ := lioss.ReadDatabase("../../testdata/test.liossgz")
+	identifier, _ := lioss.NewIdentifier("5gram", 0.75, db)
+	policy := &lioss.Policy{Allow: []string{"Apache-*", "BSD"}, Deny: []string{"GPLv*"}}
+	buffer := bytes.NewBuffer([]byte{})
+	printer := newSarifPrinter(buffer, identifier)
+	printer.PrintError(errors.New("nosuch: not found"))
+	for _, path := range []string{"../../testdata/project3.jar", "../../testdata/project2", "../../testdata/project4"} {
+		project, _ := lioss.NewProject(path)
+		defer project.Close()
+		result := identifier.IdentifyProject(project)
+		printer.PrintProject(result, policy.Evaluate(result))
+	}
+	if err := printer.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err.Error())
+	}
+	log := new(sarifLog)
+	if err := json.Unmarshal(buffer.Bytes(), log); err != nil {
+		t.Fatalf("output is not valid JSON: %s", err.Error())
+	}
+	run := log.Runs[0]
+	if run.Invocations[0].ExecutionSuccessful || len(run.Invocations[0].ToolExecutionNotifications) != 1 {
+		t.Errorf("invocation did not match, got %v", run.Invocations[0])
+	}
+	wontRules := []string{"denied-license", "no-license-file"}
+	wontURIs := []string{"../../testdata/project2/license.txt", "../../testdata/project4"}
+	if len(run.Results) != len(wontRules) {
+		t.Fatalf("size of results did not match, wont %d, got %d", len(wontRules), len(run.Results))
+	}
+	for i, result := range run.Results {
+		location := result.Locations[0].PhysicalLocation.ArtifactLocation
+		if result.RuleID != wontRules[i] || location.URI != wontURIs[i] || result.Level != "error" {
+			t.Errorf("result %d did not match, wont %s (%s), got %s (%s, %s)", i, wontRules[i], wontURIs[i], result.RuleID, location.URI, result.Level)
+		}
+		if sarifRules[result.RuleIndex].ID != result.RuleID {
+			t.Errorf("ruleIndex of %s did not match", result.RuleID)
+		}
+	}
+}
+
+func TestSarifLocationInArchive(t *testing.T) {
+	db, _ := lioss.ReadDatabase("../../testdata/test.liossgz")
+	identifier, _ := lioss.NewIdentifier("5gram", 0.75, db)
+	policy := &lioss.Policy{Allow: []string{"Apache-*"}, Deny: []string{"BSD"}}
+	project, _ := lioss.NewProject("../../testdata/project3.jar")
+	defer project.Close()
+	printer := newSarifPrinter(bytes.NewBuffer([]byte{}), identifier)
+	result := identifier.IdentifyProject(project)
+	printer.PrintProject(result, policy.Evaluate(result))
+	results := printer.run.Results
+	if len(results) != 1 || results[0].RuleID != "denied-license" {
+		t.Fatalf("results did not match, got %v", results)
+	}
+	location := results[0].Locations[0].PhysicalLocation.ArtifactLocation
+	artifact := printer.run.Artifacts[*location.Index]
+	if location.URI != "project3/subproject/license" || artifact.ParentIndex == nil {
+		t.Fatalf("location did not match, got %s", location.URI)
+	}
+	if parent := printer.run.Artifacts[*artifact.ParentIndex]; parent.Location.URI != "../../testdata/project3.jar" {
+		t.Errorf("parent of the location did not match, got %s", parent.Location.URI)
+	}
+}
@@ -0,0 +1,534 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"github.com/tamada/lioss"
+	"github.com/tamada/lioss/lib"
+)
+
+type serveOptions struct {
+	liossOptions
+	listen  string
+	maxSize int64
+}
+
+func serveHelpMessage(appName string) string {
+	app := filepath.Base(appName)
+	return fmt.Sprintf(`%s serve [OPTIONS]
+OPTIONS
+        --database-path <PATH>     specifies the database path.
+        --database-type <TYPE>     specifies the database type. Default is osi.
+    -a, --algorithm <ALGORITHM>    specifies the default algorithm. Default is 5gram.
+    -t, --threshold <THRESHOLD>    specifies the default threshold.
+                                   Default is the calibrated threshold in the database, or 0.75.
+    -l, --listen <ADDR>            specifies the listen address. Default is ":8080".
+        --max-size <BYTES>         specifies the maximum size of request body. Default is 10485760 (10MB).
+    -h, --help                     prints this message.
+ENDPOINTS
+    POST /api/identify             identifies the license of the posted text, or the uploaded archive
+                                   (multipart/form-data with "file" field).
+                                   Query parameters "algorithm", "threshold", "top", and "min-margin" are available.
+    GET  /api/licenses             lists the license names in the database.
+    GET  /api/database             shows the meta information of the database.
+    GET  /health                   health check.`, app)
+}
+
+func buildServeFlagSet() (*flag.FlagSet, *serveOptions) {
+	var opts = new(serveOptions)
+	var flags = flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Println(serveHelpMessage("lioss")) }
+	flags.BoolVarP(&opts.helpFlag, "help", "h", false, "print this message")
+	flags.StringVarP(&opts.algorithm, "algorithm", "a", "5gram", "specifies the default algorithm")
+	flags.StringVarP(&opts.dbtype, "database-type", "d", "osi", "specifies the database type")
+	flags.StringVarP(&opts.dbPath, "database-path", "p", "", "specifies the database path")
+	flags.Float64VarP(&opts.threshold, "threshold", "t", 0.75, "specifies the default threshold")
+	flags.StringVarP(&opts.listen, "listen", "l", ":8080", "specifies the listen address")
+	flags.Int64Var(&opts.maxSize, "max-size", 10*1024*1024, "specifies the maximum size of request body")
+	return flags, opts
+}
+
+func parseServeOptions(args []string) (*serveOptions, int, error) {
+	flags, opts := buildServeFlagSet()
+	if err := flags.Parse(args); err != nil {
+		return nil, 1, err
+	}
+	if opts.isHelpFlag() {
+		return nil, 0, fmt.Errorf("%s", serveHelpMessage("lioss"))
+	}
+	validators := [](func(opts *liossOptions) error){
+		isValidAlgorithm, isValidThreshold, isValidDBPath, isValidDBType,
+	}
+	for _, validator := range validators {
+		if err := validator(&opts.liossOptions); err != nil {
+			return nil, 2, err
+		}
+	}
+	opts.thresholdFlag = flags.Changed("threshold")
+	if opts.maxSize <= 0 {
+		return nil, 2, fmt.Errorf("%d: max-size must be positive", opts.maxSize)
+	}
+	return opts, 0, nil
+}
+
+func goServe(args []string) int {
+	opts, status, err := parseServeOptions(args)
+	if err != nil {
+		fmt.Println(err.Error())
+		return status
+	}
+	db, err := loadDatabase(&opts.liossOptions)
+	if err != nil {
+		return printErrors(err, 1)
+	}
+	server, err := newServer(db, opts)
+	if err != nil {
+		return printErrors(err, 2)
+	}
+	fmt.Printf("listen on %s\n", opts.listen)
+	if err := newHTTPServer(opts.listen, server.handler()).ListenAndServe(); err != nil {
+		return printErrors(err, 3)
+	}
+	return 0
+}
+
+/*
+readHeaderTimeout, readTimeout, and writeTimeout are the timeouts of the server, not to be occupied by the slow clients.
+writeTimeout includes the time for identifying the licenses of the uploaded archive.
+*/
+const (
+	readHeaderTimeout = 10 * time.Second
+	readTimeout       = 1 * time.Minute
+	writeTimeout      = 2 * time.Minute
+)
+
+func newHTTPServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{Addr: addr, Handler: handler,
+		ReadHeaderTimeout: readHeaderTimeout, ReadTimeout: readTimeout, WriteTimeout: writeTimeout}
+}
+
+/*
+maxIdentifiers is the maximum number of the identifiers cached in the server.
+*/
+const maxIdentifiers = 16
+
+/*
+server serves the REST API of lioss.
+The database is loaded once, and identifiers are created for each algorithm on demand, and shared among requests.
+The identifiers are cached up to maxIdentifiers, and the least recently used one is evicted.
+*/
+type server struct {
+	db               *lioss.Database
+	defaultAlgorithm string
+	defaultThreshold float64
+	thresholdFlag    bool
+	maxSize          int64
+	mutex            sync.Mutex
+	identifiers      map[string]*lioss.Identifier
+	/* recents holds the keys of identifiers from the least recently used one. */
+	recents []string
+}
+
+func newServer(db *lioss.Database, opts *serveOptions) (*server, error) {
+	server := &server{db: db, defaultAlgorithm: opts.algorithm, defaultThreshold: opts.threshold, thresholdFlag: opts.thresholdFlag, maxSize: opts.maxSize, identifiers: map[string]*lioss.Identifier{}, recents: []string{}}
+	if _, err := server.identifier(opts.algorithm); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/api/identify", s.handleIdentify)
+	mux.HandleFunc("/api/licenses", s.handleLicenses)
+	mux.HandleFunc("/api/database", s.handleDatabase)
+	return mux
+}
+
+/*
+identifier returns the identifier of the given algorithm.
+The identifier is created at the first call, and the returned identifier must not be modified for sharing.
+The identifiers are cached by the normalized names of the algorithms (see identifierKey),
+and created out of the lock, since the creation prepares the algorithm with the whole database.
+*/
+func (s *server) identifier(algorithmName string) (*lioss.Identifier, error) {
+	algorithm, err := lioss.NewAlgorithm(algorithmName)
+	if err != nil {
+		return nil, err
+	}
+	key := identifierKey(algorithm)
+	if identifier := s.cachedIdentifier(key); identifier != nil {
+		return identifier, nil
+	}
+	identifier, err := lioss.NewIdentifier(algorithmName, s.defaultThreshold, s.db)
+	if err != nil {
+		return nil, err
+	}
+	if !s.thresholdFlag {
+		identifier.Threshold = s.db.DefaultThreshold(identifier.Comparator.String())
+	}
+	return s.storeIdentifier(key, identifier), nil
+}
+
+/*
+identifierKey returns the key of the given algorithm for the cache,
+which consists of the name of the algorithm, and the parameters (e.g., "bm25;b=0.75;k1=1.2").
+*/
+func identifierKey(algorithm lioss.Algorithm) string {
+	key := algorithm.String()
+	parameterized, ok := algorithm.(lioss.Parameterized)
+	if !ok {
+		return key
+	}
+	params := parameterized.Parameters()
+	names := []string{}
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		key = fmt.Sprintf("%s;%s=%g", key, name, params[name])
+	}
+	return key
+}
+
+func (s *server) cachedIdentifier(key string) *lioss.Identifier {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	identifier, ok := s.identifiers[key]
+	if ok {
+		s.touch(key)
+	}
+	return identifier
+}
+
+/*
+storeIdentifier stores the given identifier into the cache, and returns the cached identifier.
+If another request has stored the identifier of the same key, this function returns it instead of the given one.
+*/
+func (s *server) storeIdentifier(key string, identifier *lioss.Identifier) *lioss.Identifier {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if cached, ok := s.identifiers[key]; ok {
+		s.touch(key)
+		return cached
+	}
+	s.identifiers[key] = identifier
+	s.recents = append(s.recents, key)
+	for len(s.recents) > maxIdentifiers {
+		delete(s.identifiers, s.recents[0])
+		s.recents = s.recents[1:]
+	}
+	return identifier
+}
+
+/*
+touch marks the given key as the most recently used one. The caller must hold the lock.
+*/
+func (s *server) touch(key string) {
+	for i, recent := range s.recents {
+		if recent == key {
+			s.recents = append(append(s.recents[:i:i], s.recents[i+1:]...), key)
+			return
+		}
+	}
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+type fileResult struct {
+	ID         string           `json:"id"`
+	Results    []*lioss.Result  `json:"results"`
+	Copyrights []*lib.Copyright `json:"copyrights,omitempty"`
+	Ambiguity  *lioss.Ambiguity `json:"ambiguity,omitempty"`
+}
+
+type identifyResponse struct {
+	Algorithm string        `json:"algorithm"`
+	Threshold float64       `json:"threshold"`
+	Files     []*fileResult `json:"files"`
+}
+
+type databaseResponse struct {
+	Timestamp          *lioss.Time `json:"create-at"`
+	LicenseListVersion string      `json:"license-list-version,omitempty"`
+	Algorithms         []string    `json:"algorithms"`
+	LicenseCount       int         `json:"license-count"`
+	ExceptionCount     int         `json:"exception-count"`
+}
+
+type licensesResponse struct {
+	Licenses   []string `json:"licenses"`
+	Exceptions []string `json:"exceptions"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &errorResponse{Message: err.Error()})
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s: method not allowed", r.Method))
+	return false
+}
+
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if allowMethod(w, r, http.MethodGet) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+func names(licenses []*lioss.License) []string {
+	results := []string{}
+	for _, license := range licenses {
+		results = append(results, license.Name)
+	}
+	sort.Strings(results)
+	return results
+}
+
+func (s *server) handleLicenses(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	identifier, err := s.identifier(s.defaultAlgorithm)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &licensesResponse{Licenses: names(identifier.Entries()), Exceptions: names(identifier.ExceptionEntries())})
+}
+
+func (s *server) handleDatabase(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	identifier, err := s.identifier(s.defaultAlgorithm)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	algorithms := []string{}
+	for name := range s.db.Data {
+		algorithms = append(algorithms, name)
+	}
+	sort.Strings(algorithms)
+	writeJSON(w, http.StatusOK, &databaseResponse{
+		Timestamp:          s.db.Timestamp,
+		LicenseListVersion: s.db.LicenseListVersion,
+		Algorithms:         algorithms,
+		LicenseCount:       len(identifier.Entries()),
+		ExceptionCount:     len(identifier.ExceptionEntries()),
+	})
+}
+
+/*
+requestIdentifier returns the identifier for the request.
+If the request specifies the threshold, top, and/or min-margin, this function returns the copy of the shared identifier with the given values.
+*/
+func (s *server) requestIdentifier(r *http.Request) (*lioss.Identifier, error) {
+	algorithm := r.URL.Query().Get("algorithm")
+	if algorithm == "" {
+		algorithm = s.defaultAlgorithm
+	}
+	if err := isValidAlgorithm(&liossOptions{algorithm: algorithm}); err != nil {
+		return nil, err
+	}
+	shared, err := s.identifier(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	query := r.URL.Query()
+	if query.Get("threshold") == "" && query.Get("top") == "" && query.Get("min-margin") == "" {
+		return shared, nil
+	}
+	identifier := *shared
+	if err := applyQuery(&identifier, query); err != nil {
+		return nil, err
+	}
+	return &identifier, nil
+}
+
+/*
+applyQuery updates the threshold, the number of results, and the minimum margin of the given identifier by the query parameters.
+*/
+func applyQuery(identifier *lioss.Identifier, query url.Values) error {
+	opts := &liossOptions{threshold: identifier.Threshold, top: identifier.TopN, minMargin: identifier.MinMargin}
+	var err error
+	if value := query.Get("threshold"); value != "" {
+		if opts.threshold, err = strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%s: invalid threshold", value)
+		}
+	}
+	if value := query.Get("top"); value != "" {
+		if opts.top, err = strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%s: invalid top", value)
+		}
+	}
+	if value := query.Get("min-margin"); value != "" {
+		if opts.minMargin, err = strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%s: invalid min-margin", value)
+		}
+	}
+	for _, validator := range [](func(opts *liossOptions) error){isValidThreshold, isValidTop, isValidMinMargin} {
+		if err := validator(opts); err != nil {
+			return err
+		}
+	}
+	identifier.Threshold, identifier.TopN, identifier.MinMargin = opts.threshold, opts.top, opts.minMargin
+	return nil
+}
+
+func (s *server) handleIdentify(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	identifier, err := s.requestIdentifier(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	r.Body = newLimitedBody(w, r.Body, s.maxSize)
+	project, cleanup, err := createProject(r)
+	if err != nil {
+		writeError(w, requestErrorStatus(err), err)
+		return
+	}
+	defer cleanup()
+	defer project.Close()
+	result := identifier.IdentifyProjectContext(r.Context(), project)
+	if result.Err != nil {
+		writeError(w, http.StatusInternalServerError, result.Err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &identifyResponse{Algorithm: identifier.Comparator.String(), Threshold: identifier.Threshold, Files: toFileResults(result)})
+}
+
+func toFileResults(result *lioss.ProjectResult) []*fileResult {
+	results := []*fileResult{}
+	for _, file := range result.Files {
+		results = append(results, &fileResult{ID: file.File.ID(), Results: file.Results, Copyrights: file.Copyrights, Ambiguity: file.Ambiguity})
+	}
+	return results
+}
+
+/*
+errRequestTooLarge is the error of reading the request body larger than the maximum size.
+*/
+var errRequestTooLarge = errors.New("request body too large")
+
+/*
+limitedBody is the request body limited by http.MaxBytesReader, and returns errRequestTooLarge when the body exceeds the limit.
+*/
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func newLimitedBody(w http.ResponseWriter, body io.ReadCloser, maxSize int64) *limitedBody {
+	return &limitedBody{ReadCloser: http.MaxBytesReader(w, body, maxSize), remaining: maxSize}
+}
+
+/*
+Read reads the request body, and replaces the error of http.MaxBytesReader after reading the maximum size by errRequestTooLarge.
+*/
+func (body *limitedBody) Read(p []byte) (int, error) {
+	n, err := body.ReadCloser.Read(p)
+	body.remaining -= int64(n)
+	if err != nil && err != io.EOF && body.remaining <= 0 {
+		return n, errRequestTooLarge
+	}
+	return n, err
+}
+
+func requestErrorStatus(err error) int {
+	if errors.Is(err, errRequestTooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+/*
+createProject creates the project from the request.
+The posted text is identified in memory, and the uploaded archive is stored into a temporary directory.
+The returned cleanup function removes the temporary directory.
+*/
+func createProject(r *http.Request) (lioss.Project, func(), error) {
+	file, header, err := r.FormFile("file")
+	if err == http.ErrNotMultipart {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+		return lioss.NewTextProject("", string(data)), func() {}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	return createArchiveProject(file, uploadedName(header.Filename))
+}
+
+func createArchiveProject(reader io.Reader, name string) (lioss.Project, func(), error) {
+	dir, err := ioutil.TempDir("", "lioss-serve-")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	path, err := storeReader(reader, filepath.Join(dir, name))
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	project, err := lioss.NewProject(path)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return project, cleanup, nil
+}
+
+func uploadedName(fileName string) string {
+	name := filepath.Base(filepath.ToSlash(fileName))
+	if name == "." || name == "/" || name == ".." {
+		return "archive"
+	}
+	return name
+}
+
+func storeReader(reader io.Reader, path string) (string, error) {
+	writer, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer writer.Close()
+	if _, err := io.Copy(writer, reader); err != nil {
+		return "", err
+	}
+	return path, nil
+}
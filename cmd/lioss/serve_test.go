@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tamada/lioss"
+)
+
+func createTestServer(t *testing.T, maxSize int64) *httptest.Server {
+	db, err := lioss.ReadDatabase("../../testdata/test.liossgz")
+	if err != nil {
+		t.Fatalf("database load error: %s", err.Error())
+	}
+	opts := &serveOptions{liossOptions: liossOptions{algorithm: "5gram", threshold: 0.75}, maxSize: maxSize}
+	server, err := newServer(db, opts)
+	if err != nil {
+		t.Fatalf("newServer failed: %s", err.Error())
+	}
+	return httptest.NewServer(server.handler())
+}
+
+func readFile(path string) []byte {
+	file, _ := os.Open(path)
+	defer file.Close()
+	data, _ := ioutil.ReadAll(file)
+	return data
+}
+
+func TestServeIdentifyText(t *testing.T) {
+	ts := createTestServer(t, 1024*1024)
+	defer ts.Close()
+	testdata := []struct {
+		query      string
+		path       string
+		wontStatus int
+		wontName   string
+	}{
+		{"", "../../data/misc/MIT", http.StatusOK, "MIT"},
+		{"?algorithm=wordfreq&threshold=0.9", "../../data/misc/WTFPL", http.StatusOK, "WTFPL"},
+		{"?algorithm=unknown", "../../data/misc/MIT", http.StatusBadRequest, ""},
+		{"?threshold=2.0", "../../data/misc/MIT", http.StatusBadRequest, ""},
+		{"?top=1&min-margin=0.5", "../../data/misc/GPLv3.0", http.StatusOK, "GPLv3.0"},
+		{"?top=-1", "../../data/misc/MIT", http.StatusBadRequest, ""},
+		{"?min-margin=abc", "../../data/misc/MIT", http.StatusBadRequest, ""},
+	}
+	for _, td := range testdata {
+		response, err := http.Post(ts.URL+"/api/identify"+td.query, "text/plain", bytes.NewReader(readFile(td.path)))
+		if err != nil {
+			t.Fatalf("post failed: %s", err.Error())
+		}
+		defer response.Body.Close()
+		if response.StatusCode != td.wontStatus {
+			t.Errorf("status of %s did not match, wont %d, got %d", td.query, td.wontStatus, response.StatusCode)
+		}
+		if td.wontStatus != http.StatusOK {
+			continue
+		}
+		result := &identifyResponse{}
+		json.NewDecoder(response.Body).Decode(result)
+		if len(result.Files) != 1 || len(result.Files[0].Results) == 0 || result.Files[0].Results[0].Name != td.wontName {
+			t.Errorf("result of %s did not match, wont %s, got %v", td.path, td.wontName, result.Files)
+		}
+		if strings.Contains(td.query, "top=1") && (len(result.Files[0].Results) != 1 || result.Files[0].Ambiguity == nil) {
+			t.Errorf("result of %s did not match, wont the top result with the ambiguity, got %v", td.query, result.Files[0])
+		}
+	}
+}
+
+func TestServeIdentifyArchive(t *testing.T) {
+	ts := createTestServer(t, 1024*1024)
+	defer ts.Close()
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", "project3.jar")
+	part.Write(readFile("../../testdata/project3.jar"))
+	writer.Close()
+	response, err := http.Post(ts.URL+"/api/identify?algorithm=6gram", writer.FormDataContentType(), body)
+	if err != nil {
+		t.Fatalf("post failed: %s", err.Error())
+	}
+	defer response.Body.Close()
+	result := &identifyResponse{}
+	json.NewDecoder(response.Body).Decode(result)
+	if len(result.Files) != 2 {
+		t.Fatalf("file count did not match, wont 2, got %d", len(result.Files))
+	}
+	if result.Files[0].ID != "project3/license" || result.Files[0].Results[0].Name != "Apache-License-2.0" {
+		t.Errorf("result did not match, got %s: %v", result.Files[0].ID, result.Files[0].Results)
+	}
+}
+
+func TestServeRequestTooLarge(t *testing.T) {
+	ts := createTestServer(t, 16)
+	defer ts.Close()
+	buffer := bytes.NewBuffer([]byte{})
+	writer := multipart.NewWriter(buffer)
+	part, _ := writer.CreateFormFile("file", "project3.jar")
+	part.Write(readFile("../../testdata/project3.jar"))
+	writer.Close()
+	testdata := []struct {
+		contentType string
+		body        io.Reader
+	}{
+		{"text/plain", strings.NewReader("this request body is larger than 16 bytes")},
+		{writer.FormDataContentType(), buffer},
+	}
+	for _, td := range testdata {
+		response, err := http.Post(ts.URL+"/api/identify", td.contentType, td.body)
+		if err != nil {
+			t.Fatalf("post failed: %s", err.Error())
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusRequestEntityTooLarge {
+			t.Errorf("%s: status did not match, wont %d, got %d", td.contentType, http.StatusRequestEntityTooLarge, response.StatusCode)
+		}
+	}
+}
+
+func TestRequestErrorStatus(t *testing.T) {
+	testdata := []struct {
+		err        error
+		wontStatus int
+	}{
+		{errRequestTooLarge, http.StatusRequestEntityTooLarge},
+		{fmt.Errorf("multipart: NextPart: %w", errRequestTooLarge), http.StatusRequestEntityTooLarge},
+		{errors.New("request body too large"), http.StatusBadRequest},
+		{http.ErrMissingFile, http.StatusBadRequest},
+	}
+	for _, td := range testdata {
+		if status := requestErrorStatus(td.err); status != td.wontStatus {
+			t.Errorf("requestErrorStatus(%v) did not match, wont %d, got %d", td.err, td.wontStatus, status)
+		}
+	}
+}
+
+func TestHTTPServerTimeouts(t *testing.T) {
+	server := newHTTPServer(":8080", http.NotFoundHandler())
+	if server.ReadHeaderTimeout <= 0 || server.ReadTimeout <= 0 || server.WriteTimeout <= 0 {
+		t.Errorf("timeouts of the server should be positive, got %v, %v, %v", server.ReadHeaderTimeout, server.ReadTimeout, server.WriteTimeout)
+	}
+}
+
+func TestServeGetEndpoints(t *testing.T) {
+	ts := createTestServer(t, 1024)
+	defer ts.Close()
+	testdata := []struct {
+		method     string
+		path       string
+		wontStatus int
+		wontBody   string
+	}{
+		{http.MethodGet, "/health", http.StatusOK, `"status":"ok"`},
+		{http.MethodGet, "/api/licenses", http.StatusOK, `"MIT"`},
+		{http.MethodGet, "/api/database", http.StatusOK, `"license-count":23`},
+		{http.MethodPost, "/api/licenses", http.StatusMethodNotAllowed, "method not allowed"},
+		{http.MethodGet, "/api/identify", http.StatusMethodNotAllowed, "method not allowed"},
+	}
+	for _, td := range testdata {
+		request, _ := http.NewRequest(td.method, ts.URL+td.path, nil)
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("%s %s failed: %s", td.method, td.path, err.Error())
+		}
+		defer response.Body.Close()
+		body, _ := ioutil.ReadAll(response.Body)
+		if response.StatusCode != td.wontStatus || !strings.Contains(string(body), td.wontBody) {
+			t.Errorf("%s %s did not match, wont %d (%s), got %d (%s)", td.method, td.path, td.wontStatus, td.wontBody, response.StatusCode, string(body))
+		}
+	}
+}
+
+func Example_serveHelp() {
+	goMain([]string{"lioss", "serve", "--help"})
+	// Output:
+	// lioss serve [OPTIONS]
+	// OPTIONS
+	//         --database-path <PATH>     specifies the database path.
+	//         --database-type <TYPE>     specifies the database type. Default is osi.
+	//     -a, --algorithm <ALGORITHM>    specifies the default algorithm. Default is 5gram.
+	//     -t, --threshold <THRESHOLD>    specifies the default threshold.
+	//                                    Default is the calibrated threshold in the database, or 0.75.
+	//     -l, --listen <ADDR>            specifies the listen address. Default is ":8080".
+	//         --max-size <BYTES>         specifies the maximum size of request body. Default is 10485760 (10MB).
+	//     -h, --help                     prints this message.
+	// ENDPOINTS
+	//     POST /api/identify             identifies the license of the posted text, or the uploaded archive
+	//                                    (multipart/form-data with "file" field).
+	//                                    Query parameters "algorithm", "threshold", "top", and "min-margin" are available.
+	//     GET  /api/licenses             lists the license names in the database.
+	//     GET  /api/database             shows the meta information of the database.
+	//     GET  /health                   health check.
+}
+
+func TestServerIdentifierCache(t *testing.T) {
+	db, _ := lioss.ReadDatabase("../../testdata/test.liossgz")
+	server, _ := newServer(db, &serveOptions{liossOptions: liossOptions{algorithm: "5gram", threshold: 0.75}})
+	identifier1, _ := server.identifier("ensemble:5gram,tfidf")
+	identifier2, _ := server.identifier("ENSEMBLE:5gram=1.0,tfidf")
+	if identifier1 != identifier2 {
+		t.Errorf("identifiers of the same ensemble were not shared")
+	}
+	for i := 0; i < maxIdentifiers*2; i++ {
+		if _, err := server.identifier(fmt.Sprintf("ensemble:5gram=%d,tfidf", i+2)); err != nil {
+			t.Fatalf("identifier failed: %s", err.Error())
+		}
+		if _, err := server.identifier("5gram"); err != nil {
+			t.Fatalf("identifier failed: %s", err.Error())
+		}
+	}
+	if len(server.identifiers) != maxIdentifiers || len(server.recents) != maxIdentifiers {
+		t.Errorf("size of the cache did not match, wont %d, got %d (%d)", maxIdentifiers, len(server.identifiers), len(server.recents))
+	}
+	if _, ok := server.identifiers["5gram"]; !ok {
+		t.Errorf("the recently used identifier was evicted")
+	}
+	if _, ok := server.identifiers["ensemble:5gram,tfidf"]; ok {
+		t.Errorf("the least recently used identifier was not evicted")
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tamada/lioss"
+)
+
+/*
+tableHeader shows the columns of the tabular formats (csv, and markdown).
+*/
+var tableHeader = []string{"project", "file", "rank", "license", "probability", "algorithm"}
+
+/*
+tableWriter writes the rows of a table in a format.
+*/
+type tableWriter interface {
+	Write(row []string) error
+	Flush() error
+}
+
+/*
+tablePrinter prints one row per the candidates of license files.
+The license files without candidates, and the projects without license files are printed as the rows with empty columns.
+The errors, and the policy violations are printed into errWriter (stderr) for keeping the table parseable.
+*/
+type tablePrinter struct {
+	writer     tableWriter
+	errWriter  io.Writer
+	algorithm  string
+	violations []*lioss.Violation
+	err        error
+}
+
+func newTablePrinter(writer tableWriter, errWriter io.Writer, identifier *lioss.Identifier) *tablePrinter {
+	printer := &tablePrinter{writer: writer, errWriter: errWriter, algorithm: identifier.Comparator.String(), violations: []*lioss.Violation{}}
+	printer.write(tableHeader)
+	return printer
+}
+
+func (tp *tablePrinter) PrintError(err error) {
+	fmt.Fprintf(tp.errWriter, "%s\n", err.Error())
+}
+
+func (tp *tablePrinter) PrintProject(result *lioss.ProjectResult, violations []*lioss.Violation) {
+	project := result.Project
+	tp.violations = append(tp.violations, violations...)
+	if result.Err != nil {
+		tp.PrintError(fmt.Errorf("%s: %s", project.BasePath(), result.Err.Error()))
+		return
+	}
+	if len(result.Files) == 0 {
+		tp.write([]string{project.BasePath(), "", "", "", "", tp.algorithm})
+	}
+	for _, file := range result.Files {
+		if len(file.Results) == 0 {
+			tp.write([]string{project.BasePath(), file.File.ID(), "", "", "", tp.algorithm})
+		}
+		for i, candidate := range file.Results {
+			tp.write([]string{project.BasePath(), file.File.ID(), strconv.Itoa(i + 1), candidate.Expression(), fmt.Sprintf("%1.4f", candidate.Probability), tp.algorithm})
+		}
+	}
+}
+
+func (tp *tablePrinter) write(row []string) {
+	if tp.err == nil {
+		tp.err = tp.writer.Write(row)
+	}
+}
+
+func (tp *tablePrinter) Close() error {
+	for _, violation := range tp.violations {
+		fmt.Fprintf(tp.errWriter, "policy: %s\n", violation)
+	}
+	if tp.err != nil {
+		return tp.err
+	}
+	return tp.writer.Flush()
+}
+
+/*
+csvWriter writes the rows in CSV (RFC 4180).
+*/
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func (cw *csvWriter) Write(row []string) error {
+	return cw.writer.Write(row)
+}
+
+func (cw *csvWriter) Flush() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+/*
+markdownWriter writes the rows in the table of GitHub Flavored Markdown, and the first row is the header.
+*/
+type markdownWriter struct {
+	writer        io.Writer
+	headerWritten bool
+}
+
+var markdownEscaper = strings.NewReplacer(`|`, `\|`, "\n", " ")
+
+func (mw *markdownWriter) Write(row []string) error {
+	cells := []string{}
+	for _, cell := range row {
+		cells = append(cells, markdownEscaper.Replace(cell))
+	}
+	if _, err := fmt.Fprintf(mw.writer, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+		return err
+	}
+	if mw.headerWritten {
+		return nil
+	}
+	mw.headerWritten = true
+	return mw.writeDelimiter(len(row))
+}
+
+func (mw *markdownWriter) writeDelimiter(columns int) error {
+	delimiters := []string{}
+	for i := 0; i < columns; i++ {
+		delimiters = append(delimiters, "---")
+	}
+	_, err := fmt.Fprintf(mw.writer, "| %s |\n", strings.Join(delimiters, " | "))
+	return err
+}
+
+func (mw *markdownWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"testing"
+
+	"github.com/tamada/lioss"
+)
+
+func printTable(t *testing.T, writer func(buffer *bytes.Buffer) tableWriter) (string, string) {
+	db, _ := lioss.ReadDatabase("../../testdata/test.liossgz")
+	identifier, _ := lioss.NewIdentifier("6gram", 0.75, db)
+	buffer := bytes.NewBuffer([]byte{})
+	errBuffer := bytes.NewBuffer([]byte{})
+	printer := newTablePrinter(writer(buffer), errBuffer, identifier)
+	printer.PrintError(errors.New("nosuch: not found"))
+	for _, path := range []string{"../../testdata/project3.jar", "../../testdata/project4"} {
+		project, _ := lioss.NewProject(path)
+		defer project.Close()
+		printer.PrintProject(identifier.IdentifyProject(project), []*lioss.Violation{})
+	}
+	if err := printer.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err.Error())
+	}
+	return buffer.String(), errBuffer.String()
+}
+
+func TestCSVPrinter(t *testing.T) {
+	got, gotErr := printTable(t, func(buffer *bytes.Buffer) tableWriter {
+		return &csvWriter{writer: csv.NewWriter(buffer)}
+	})
+	wont := `project,file,rank,license,probability,algorithm
+../../testdata/project3.jar,project3/license,1,Apache-License-2.0,1.0000,6gram
+../../testdata/project3.jar,project3/subproject/license,1,BSD,1.0000,6gram
+../../testdata/project4,,,,,6gram
+`
+	if got != wont {
+		t.Errorf("csv did not match, wont %s, got %s", wont, got)
+	}
+	if gotErr != "nosuch: not found\n" {
+		t.Errorf("errors did not match, got %s", gotErr)
+	}
+}
+
+func TestMarkdownPrinter(t *testing.T) {
+	got, _ := printTable(t, func(buffer *bytes.Buffer) tableWriter {
+		return &markdownWriter{writer: buffer}
+	})
+	wont := `| project | file | rank | license | probability | algorithm |
+| --- | --- | --- | --- | --- | --- |
+| ../../testdata/project3.jar | project3/license | 1 | Apache-License-2.0 | 1.0000 | 6gram |
+| ../../testdata/project3.jar | project3/subproject/license | 1 | BSD | 1.0000 | 6gram |
+| ../../testdata/project4 |  |  |  |  | 6gram |
+`
+	if got != wont {
+		t.Errorf("markdown did not match, wont %s, got %s", wont, got)
+	}
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	writer := &markdownWriter{writer: buffer, headerWritten: true}
+	writer.Write([]string{"a|b", "c\nd"})
+	if wont := "| a\\|b | c d |\n"; buffer.String() != wont {
+		t.Errorf("escaped row did not match, wont %s, got %s", wont, buffer.String())
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/tamada/lioss"
+	"github.com/tamada/lioss/lib"
+)
+
+/*
+templateModel is the data given to the template of --template option.
+The fields are documented in docs/content/usage.md, and keep the compatibility for the user templates.
+*/
+type templateModel struct {
+	Database *templateDatabase
+	Projects []*templateProject
+	Errors   []string
+}
+
+/*
+templateDatabase shows the database and the identification settings.
+*/
+type templateDatabase struct {
+	Path               string
+	LicenseListVersion string
+	Timestamp          string
+	Normalization      string
+	Algorithm          string
+	Threshold          float64
+}
+
+type templateProject struct {
+	Path       string
+	Err        string
+	Files      []*templateFile
+	Violations []*lioss.Violation
+}
+
+type templateFile struct {
+	ID         string
+	Path       string
+	Results    []*lioss.Result
+	Copyrights []*lib.Copyright
+	Ambiguity  *lioss.Ambiguity
+}
+
+/*
+templatePrinter collects the results into templateModel, and executes the template on Close.
+*/
+type templatePrinter struct {
+	writer   io.Writer
+	template *template.Template
+	model    *templateModel
+}
+
+func templateFuncs(db *lioss.Database) template.FuncMap {
+	return template.FuncMap{
+		"percent": func(probability float64) string {
+			return fmt.Sprintf("%.2f%%", probability*100)
+		},
+		"probability": func(probability float64) string {
+			return fmt.Sprintf("%1.4f", probability)
+		},
+		"join": strings.Join,
+		"spdx": func(licenseName string) *lib.LicenseMeta {
+			return db.Meta(licenseName)
+		},
+		"classify": lioss.Classify,
+	}
+}
+
+func newTemplatePrinter(writer io.Writer, identifier *lioss.Identifier, opts *liossOptions) (*templatePrinter, error) {
+	tmpl, err := template.New(filepath.Base(opts.template)).Funcs(templateFuncs(identifier.Database)).ParseFiles(opts.template)
+	if err != nil {
+		return nil, err
+	}
+	model := &templateModel{Database: newTemplateDatabase(identifier, opts), Projects: []*templateProject{}, Errors: []string{}}
+	return &templatePrinter{writer: writer, template: tmpl, model: model}, nil
+}
+
+func newTemplateDatabase(identifier *lioss.Identifier, opts *liossOptions) *templateDatabase {
+	db := identifier.Database
+	database := &templateDatabase{Path: opts.dbPath, LicenseListVersion: db.LicenseListVersion, Normalization: db.Normalization,
+		Algorithm: identifier.Comparator.String(), Threshold: identifier.Threshold}
+	if db.Timestamp != nil {
+		database.Timestamp = db.Timestamp.String()
+	}
+	return database
+}
+
+func (tp *templatePrinter) PrintError(err error) {
+	tp.model.Errors = append(tp.model.Errors, err.Error())
+}
+
+func (tp *templatePrinter) PrintProject(result *lioss.ProjectResult, violations []*lioss.Violation) {
+	project := &templateProject{Path: result.Project.BasePath(), Files: []*templateFile{}, Violations: violations}
+	tp.model.Projects = append(tp.model.Projects, project)
+	if result.Err != nil {
+		project.Err = result.Err.Error()
+		return
+	}
+	for _, file := range result.Files {
+		project.Files = append(project.Files, &templateFile{ID: file.File.ID(), Path: fmt.Sprintf("%s/%s", project.Path, file.File.ID()),
+			Results: file.Results, Copyrights: file.Copyrights, Ambiguity: file.Ambiguity})
+	}
+}
+
+func (tp *templatePrinter) Close() error {
+	return tp.template.Execute(tp.writer, tp.model)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tamada/lioss"
+	"github.com/tamada/lioss/lib"
+)
+
+const testTemplate = `{{.Database.Algorithm}} {{probability .Database.Threshold}}
+{{range .Projects}}{{.Path}}{{with .Err}}: {{.}}{{end}}
+{{range .Files}}  {{.Path}}{{range .Results}} {{.Name}}={{percent .Probability}}{{with spdx .Name}} ({{.Names.FullName}}){{end}}{{with classify .Name}} [{{.Category}}]{{end}}{{end}}
+{{else}}  no license files
+{{end}}{{end}}{{range .Errors}}error: {{.}}
+{{end}}`
+
+func TestTemplatePrinter(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "lioss")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "result.tmpl")
+	ioutil.WriteFile(path, []byte(testTemplate), 0644)
+
+	db, _ := lioss.ReadDatabase("../../testdata/test.liossgz")
+	db.PutMeta("BSD", &lib.LicenseMeta{Names: &lib.Names{ShortName: "BSD", FullName: "BSD License"}})
+	identifier, _ := lioss.NewIdentifier("6gram", 0.75, db)
+	buffer := bytes.NewBuffer([]byte{})
+	printer, err := newTemplatePrinter(buffer, identifier, &liossOptions{template: path})
+	if err != nil {
+		t.Fatalf("newTemplatePrinter failed: %s", err.Error())
+	}
+	printer.PrintError(errors.New("nosuch: not found"))
+	for _, path := range []string{"../../testdata/project3.jar", "../../testdata/project4"} {
+		project, _ := lioss.NewProject(path)
+		defer project.Close()
+		printer.PrintProject(identifier.IdentifyProject(project), []*lioss.Violation{})
+	}
+	if err := printer.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err.Error())
+	}
+	wont := `6gram 0.7500
+../../testdata/project3.jar
+  ../../testdata/project3.jar/project3/license Apache-License-2.0=100.00%
+  ../../testdata/project3.jar/project3/subproject/license BSD=100.00% (BSD License)
+../../testdata/project4
+  no license files
+error: nosuch: not found
+`
+	if buffer.String() != wont {
+		t.Errorf("template output did not match, wont %s, got %s", wont, buffer.String())
+	}
+}
+
+func TestInvalidTemplate(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "lioss")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "invalid.tmpl")
+	ioutil.WriteFile(path, []byte(`{{range .Projects}}`), 0644)
+	testdata := []struct {
+		args       []string
+		wontStatus int
+	}{
+		{[]string{"lioss", "--database-path", "../../testdata/test.liossgz", "--template", path, "../../testdata/project4"}, 2},
+		{[]string{"lioss", "--template", "no/such/file.tmpl", "../../testdata/project4"}, 2},
+		{[]string{"lioss", "--template", path, "--format", "csv", "../../testdata/project4"}, 2},
+	}
+	for _, td := range testdata {
+		if status := goMain(td.args); status != td.wontStatus {
+			t.Errorf("%v: status did not match, wont %d, got %d", td.args, td.wontStatus, status)
+		}
+	}
+}
@@ -3,8 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
+
+	"github.com/tamada/lioss"
 )
 
 func contains(word string, set []string) bool {
@@ -17,14 +18,8 @@ func contains(word string, set []string) bool {
 }
 
 func isValidAlgorithm(opts *liossOptions) error {
-	if strings.HasSuffix(opts.algorithm, "gram") {
-		_, err := strconv.Atoi(strings.ReplaceAll(opts.algorithm, "gram", ""))
-		return err
-	}
-	if !contains(opts.algorithm, []string{"tfidf", "wordfreq"}) {
-		return fmt.Errorf("%s: unknown algorithm", opts.algorithm)
-	}
-	return nil
+	_, err := lioss.NewAlgorithm(opts.algorithm)
+	return err
 }
 
 func isValidThreshold(opts *liossOptions) error {
@@ -34,6 +29,27 @@ func isValidThreshold(opts *liossOptions) error {
 	return fmt.Errorf("%f: threshold must be 0.0 to 1.0", opts.threshold)
 }
 
+func isValidJobs(opts *liossOptions) error {
+	if opts.jobs > 0 {
+		return nil
+	}
+	return fmt.Errorf("%d: jobs must be positive", opts.jobs)
+}
+
+func isValidTop(opts *liossOptions) error {
+	if opts.top >= 0 {
+		return nil
+	}
+	return fmt.Errorf("%d: top must not be negative", opts.top)
+}
+
+func isValidMinMargin(opts *liossOptions) error {
+	if opts.minMargin >= 0.0 && opts.minMargin <= 1.0 {
+		return nil
+	}
+	return fmt.Errorf("%f: min-margin must be 0.0 to 1.0", opts.minMargin)
+}
+
 func isValidArgs(args []string) error {
 	if len(args) > 0 {
 		return nil
@@ -65,9 +81,30 @@ func isValidDBPath(opts *liossOptions) error {
 	return nil
 }
 
+func isValidPolicyPath(opts *liossOptions) error {
+	if opts.policy != "" && !existsFile(opts.policy) {
+		return fmt.Errorf("%s: file not found", opts.policy)
+	}
+	return nil
+}
+
+func isValidTemplatePath(opts *liossOptions) error {
+	if opts.template != "" && !existsFile(opts.template) {
+		return fmt.Errorf("%s: file not found", opts.template)
+	}
+	return nil
+}
+
+func isValidFormat(opts *liossOptions) error {
+	if contains(strings.ToLower(opts.format), formats) {
+		return nil
+	}
+	return fmt.Errorf("%s: unknown format", opts.format)
+}
+
 func validateOptions(opts *liossOptions, args []string) error {
 	validators := [](func(opts *liossOptions) error){
-		isValidAlgorithm, isValidThreshold, isValidDBPath, isValidDBType,
+		isValidAlgorithm, isValidThreshold, isValidDBPath, isValidDBType, isValidJobs, isValidTop, isValidMinMargin, isValidPolicyPath, isValidTemplatePath,
 	}
 	for _, validator := range validators {
 		if err := validator(opts); err != nil {
@@ -2,27 +2,51 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	flag "github.com/spf13/pflag"
 	"github.com/tamada/lioss"
+	"github.com/tamada/lioss/lib"
 )
 
 type mkliossdbOptions struct {
-	dest     string
-	format   string
-	helpFlag bool
-	args     []string
+	dest          string
+	format        string
+	updateFlag    bool
+	removes       []string
+	fpRate        float64
+	normalize     string
+	normalizeFlag bool
+	withTexts     bool
+	bm25          string
+	helpFlag      bool
+	args          []string
 }
 
 func helpMessage() string {
-	return `mkliossdb [OPTIONS] <LICENSEs...>
+	return fmt.Sprintf(`mkliossdb [OPTIONS] <LICENSEs...>
 OPTIONS
     -d, --dest <DEST>        specifies the destination file path. Default is 'default.liossdb'
+    -u, --update             updates the existing database specified by dest option,
+                             instead of creating a new database.
+    -r, --remove <NAMES>     removes the licenses of the given names from the database (comma separated).
+                             This option is available with update option.
+        --false-positive-rate <RATE>
+                             specifies the target false-positive rate for calibrating the thresholds.
+                             Default is 0.01.
+        --normalization <STEPS>
+                             specifies the normalization steps (comma separated). Default is all.
+                             Available values are: %s, all, and none.
+                             In update mode, the normalization of the database is used.
+        --with-texts         stores the license texts into the database for the reports.
+        --bm25 <PARAMS>      specifies the parameters of bm25 stored into the database (e.g., k1=1.5,b=0.5).
+                             Default is k1=%g,b=%g, or the parameters in the database in update mode.
     -h, --help               print this message.
 LICENSE
-    specifies license files.`
+    specifies license files.`, strings.Join(lib.NormalizeStepNames(), ", "), lioss.DefaultBM25K1, lioss.DefaultBM25B)
 }
 
 func buildFlagSet() (*flag.FlagSet, *mkliossdbOptions) {
@@ -31,6 +55,12 @@ func buildFlagSet() (*flag.FlagSet, *mkliossdbOptions) {
 	flags.Usage = func() { fmt.Println(helpMessage()) }
 	flags.BoolVarP(&opts.helpFlag, "help", "h", false, "print this message.")
 	flags.StringVarP(&opts.dest, "dest", "d", "default.liossdb", "specifies the destination file path.")
+	flags.BoolVarP(&opts.updateFlag, "update", "u", false, "updates the existing database.")
+	flags.StringSliceVarP(&opts.removes, "remove", "r", []string{}, "removes the licenses from the database.")
+	flags.Float64Var(&opts.fpRate, "false-positive-rate", lioss.DefaultFalsePositiveRate, "specifies the target false-positive rate.")
+	flags.StringVar(&opts.normalize, "normalization", "all", "specifies the normalization steps.")
+	flags.BoolVar(&opts.withTexts, "with-texts", false, "stores the license texts into the database.")
+	flags.StringVar(&opts.bm25, "bm25", "", "specifies the parameters of bm25.")
 	return flags, opts
 }
 
@@ -42,62 +72,139 @@ func parseOptions(args []string) (*mkliossdbOptions, error) {
 	if len(flags.Args()) > 1 {
 		opts.args = flags.Args()[1:]
 	}
+	opts.normalizeFlag = flags.Changed("normalization")
+	if len(opts.removes) > 0 && !opts.updateFlag {
+		return nil, fmt.Errorf("remove option requires update option")
+	}
+	if opts.fpRate < 0 || opts.fpRate > 1 {
+		return nil, fmt.Errorf("%f: false-positive rate must be 0.0 to 1.0", opts.fpRate)
+	}
+	if _, err := lioss.NewAlgorithm(algorithmName("bm25", opts)); err != nil {
+		return nil, err
+	}
 	return opts, nil
 }
 
 func (opts *mkliossdbOptions) isHelpFlag() bool {
-	return opts.helpFlag || len(opts.args) == 0
+	return opts.helpFlag || (len(opts.args) == 0 && len(opts.removes) == 0)
 }
 
-func readLicense(file string, algo lioss.Algorithm) (*lioss.License, error) {
+func readLicense(file string, normalizer *lib.Normalizer, algo lioss.Algorithm) (*lioss.License, error) {
 	reader, err := os.Open(file)
 	if err != nil {
 		return nil, err
 	}
 	defer reader.Close()
-	return algo.Parse(reader, filepath.Base(file))
+	return lioss.ParseLicense(normalizer, algo, reader, filepath.Base(file))
 }
 
-func performEach(db *lioss.Database, args []string, algorithmName string) error {
-	fmt.Printf(`building database for algorithm "%s"...`, algorithmName)
-	algorithm, err := lioss.NewAlgorithm(algorithmName)
+/*
+algorithmName returns the name of the given algorithm with the parameters given by the options (e.g., "bm25:k1=1.5").
+*/
+func algorithmName(name string, opts *mkliossdbOptions) string {
+	if name == "bm25" && opts.bm25 != "" {
+		return name + ":" + opts.bm25
+	}
+	return name
+}
+
+func performEach(db *lioss.Database, normalizer *lib.Normalizer, changes *lioss.Changes, opts *mkliossdbOptions, name string) error {
+	fmt.Printf(`building database for algorithm "%s"...`, name)
+	algorithm, err := lioss.NewAlgorithm(algorithmName(name, opts))
 	if err != nil {
 		return err
 	}
-	for _, arg := range args {
-		license, err := readLicense(arg, algorithm)
+	for _, arg := range opts.args {
+		license, err := readLicense(arg, normalizer, algorithm)
 		if err != nil {
 			return err
 		}
-		db.Put(algorithmName, license)
+		db.Update(algorithm.String(), license, changes)
+	}
+	if err := db.StoreParameters(algorithm); err != nil {
+		return err
 	}
 	fmt.Println(`done`)
 	return nil
 }
 
-func buildDatabase(opts *mkliossdbOptions) (*lioss.Database, error) {
-	db := lioss.NewDatabase()
+func openDatabase(opts *mkliossdbOptions) (*lioss.Database, error) {
+	if opts.updateFlag {
+		return lioss.ReadDatabase(lioss.DestinationPath(opts.dest))
+	}
+	return lioss.NewDatabase(), nil
+}
+
+func removeLicenses(db *lioss.Database, changes *lioss.Changes, names []string) {
+	for _, name := range names {
+		if db.RemoveLicense(name) {
+			changes.Remove(name)
+		} else {
+			fmt.Printf("%s: license not found\n", name)
+		}
+	}
+}
+
+func buildDatabase(opts *mkliossdbOptions) (*lioss.Database, *lioss.Changes, error) {
+	db, err := openDatabase(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	normalizer, err := db.BuildNormalizer(opts.normalize, opts.updateFlag, opts.normalizeFlag)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes := lioss.NewChanges()
+	removeLicenses(db, changes, opts.removes)
+	if len(opts.args) == 0 {
+		return db, changes, nil
+	}
 	for _, algorithm := range lioss.AvailableAlgorithms {
-		err := performEach(db, opts.args, algorithm)
+		err := performEach(db, normalizer, changes, opts, algorithm)
 		if err != nil {
 			fmt.Println(err.Error())
 			continue
 		}
 	}
-	return db, nil
+	if opts.withTexts {
+		return db, changes, storeTexts(db, opts.args)
+	}
+	return db, changes, nil
+}
+
+func storeTexts(db *lioss.Database, args []string) error {
+	for _, arg := range args {
+		data, err := ioutil.ReadFile(arg)
+		if err != nil {
+			return err
+		}
+		db.PutText(filepath.Base(arg), strings.TrimSpace(string(data)))
+	}
+	return nil
 }
 
 func perform(opts *mkliossdbOptions) int {
-	db, err := buildDatabase(opts)
+	db, changes, err := buildDatabase(opts)
 	if err != nil {
 		fmt.Println(err.Error())
 		return 2
 	}
-	err = db.WriteTo(opts.dest)
+	if !changes.IsEmpty() {
+		db.Timestamp = lioss.Now()
+	}
+	if err := db.CalibrateAll(opts.fpRate); err != nil {
+		fmt.Println(err.Error())
+		return 2
+	}
+	db.BuildMinHash(lioss.DefaultMinHashParameters())
+	err = db.WriteTo(lioss.DestinationPath(opts.dest))
 	if err != nil {
 		fmt.Println(err.Error())
 		return 3
 	}
+	if opts.updateFlag {
+		fmt.Println(changes.String())
+	}
 	return 0
 }
 
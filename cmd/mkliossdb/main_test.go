@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/tamada/lioss"
+	"github.com/tamada/lioss/lib"
 )
 
 func TestPerformEach(t *testing.T) {
@@ -17,7 +18,7 @@ func TestPerformEach(t *testing.T) {
 	}
 	db := lioss.NewDatabase()
 	for _, td := range testdata {
-		err := performEach(db, td.args, td.comparator)
+		err := performEach(db, lib.DefaultNormalizer(), lioss.NewChanges(), &mkliossdbOptions{args: td.args}, td.comparator)
 		if err == nil {
 			t.Errorf("performEach(%v, %s) should fail", td.args, td.comparator)
 		}
@@ -47,11 +48,102 @@ func TestRun(t *testing.T) {
 	if err != nil {
 		t.Errorf("load failed: %s", err.Error())
 	}
-	if len(db.Data) != 11 {
+	if len(db.Data) != 14 {
 		t.Errorf("database did not fully outputed")
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	goMain([]string{"mkliossdb", "-d", "../../update.liossgz", "../../data/misc/BSD", "../../data/misc/MIT"})
+	defer os.Remove("../../update.liossgz")
+	goMain([]string{"mkliossdb", "-u", "-d", "../../update.liossgz", "--remove", "BSD", "../../data/misc/WTFPL"})
+
+	db, err := lioss.ReadDatabase("../../update.liossgz")
+	if err != nil {
+		t.Fatalf("load failed: %s", err.Error())
+	}
+	testdata := []struct {
+		licenseName string
+		wontFlag    bool
+	}{
+		{"BSD", false},
+		{"MIT", true},
+		{"WTFPL", true},
+	}
+	for _, td := range testdata {
+		for _, algorithm := range lioss.AvailableAlgorithms {
+			if db.Contains(algorithm, td.licenseName) != td.wontFlag {
+				t.Errorf("db.Contains(%s, %s) did not match, wont %v", algorithm, td.licenseName, td.wontFlag)
+			}
+		}
+	}
+	if len(db.Thresholds) != len(lioss.AvailableAlgorithms) {
+		t.Errorf("thresholds were not calibrated, got %v", db.Thresholds)
+	}
+}
+
+func TestNormalizationMismatch(t *testing.T) {
+	goMain([]string{"mkliossdb", "-d", "../../normalization.liossgz", "--normalization", "lowercase", "../../data/misc/MIT"})
+	defer os.Remove("../../normalization.liossgz")
+	testdata := []struct {
+		args       []string
+		wontStatus int
+	}{
+		{[]string{"mkliossdb", "-u", "-d", "../../normalization.liossgz", "--normalization", "all", "../../data/misc/BSD"}, 2},
+		{[]string{"mkliossdb", "-u", "-d", "../../normalization.liossgz", "--normalization", "unknown", "../../data/misc/BSD"}, 2},
+		{[]string{"mkliossdb", "-u", "-d", "../../normalization.liossgz", "../../data/misc/BSD"}, 0},
+	}
+	for _, td := range testdata {
+		if status := goMain(td.args); status != td.wontStatus {
+			t.Errorf("goMain(%v) did not match, wont %d, got %d", td.args, td.wontStatus, status)
+		}
+	}
+	db, _ := lioss.ReadDatabase("../../normalization.liossgz")
+	if db.Normalization != "v3:lowercase" {
+		t.Errorf("normalization of database did not match, wont v3:lowercase, got %s", db.Normalization)
+	}
+}
+
+func TestBM25Parameters(t *testing.T) {
+	goMain([]string{"mkliossdb", "-d", "../../bm25.liossgz", "--bm25", "k1=1.5,b=0.5", "../../data/misc/BSD", "../../data/misc/MIT"})
+	defer os.Remove("../../bm25.liossgz")
+	goMain([]string{"mkliossdb", "-u", "-d", "../../bm25.liossgz", "../../data/misc/WTFPL"})
+	db, err := lioss.ReadDatabase("../../bm25.liossgz")
+	if err != nil {
+		t.Fatalf("load failed: %s", err.Error())
+	}
+	if params := db.Parameters["bm25"]; params["k1"] != 1.5 || params["b"] != 0.5 {
+		t.Errorf("parameters of bm25 did not match, wont k1=1.5, b=0.5, got %v", params)
+	}
+	if !db.Contains("bm25", "WTFPL") {
+		t.Errorf("licenses of bm25 should be stored by the name of the algorithm")
+	}
+	identifier, err := lioss.NewIdentifier("bm25", 0.75, db)
+	if err != nil {
+		t.Fatalf("NewIdentifier failed: %s", err.Error())
+	}
+	if params := identifier.Comparator.(lioss.Parameterized).Parameters(); params["k1"] != 1.5 || params["b"] != 0.5 {
+		t.Errorf("parameters of the identifier did not match, wont k1=1.5, b=0.5, got %v", params)
+	}
+	if _, err := parseOptions([]string{"mkliossdb", "--bm25", "k1=abc", "../../data/misc/MIT"}); err == nil {
+		t.Errorf("parseOptions should be fail, because the parameters of bm25 are invalid")
+	}
+}
+
+func TestInvalidFalsePositiveRate(t *testing.T) {
+	_, err := parseOptions([]string{"mkliossdb", "--false-positive-rate", "1.5", "../../data/misc/MIT"})
+	if err == nil {
+		t.Errorf("parseOptions should be fail, because false-positive rate must be 0.0 to 1.0")
+	}
+}
+
+func TestRemoveWithoutUpdate(t *testing.T) {
+	_, err := parseOptions([]string{"mkliossdb", "--remove", "BSD"})
+	if err == nil {
+		t.Errorf("parseOptions should be fail, because remove option requires update option")
+	}
+}
+
 func TestIsHelpFlag(t *testing.T) {
 	testdata := []struct {
 		args         []string
@@ -60,6 +152,7 @@ func TestIsHelpFlag(t *testing.T) {
 		{[]string{"mkliossdb", "-h"}, true},
 		{[]string{"mkliossdb"}, true},
 		{[]string{"mkliossdb", "../../data"}, false},
+		{[]string{"mkliossdb", "-u", "-r", "BSD"}, false},
 	}
 	for _, td := range testdata {
 		opts, err := parseOptions(td.args)
@@ -78,7 +171,36 @@ func Example_pritHelp() {
 	// mkliossdb [OPTIONS] <LICENSEs...>
 	// OPTIONS
 	//     -d, --dest <DEST>        specifies the destination file path. Default is 'default.liossdb'
+	//     -u, --update             updates the existing database specified by dest option,
+	//                              instead of creating a new database.
+	//     -r, --remove <NAMES>     removes the licenses of the given names from the database (comma separated).
+	//                              This option is available with update option.
+	//         --false-positive-rate <RATE>
+	//                              specifies the target false-positive rate for calibrating the thresholds.
+	//                              Default is 0.01.
+	//         --normalization <STEPS>
+	//                              specifies the normalization steps (comma separated). Default is all.
+	//                              Available values are: copyright, list-numbering, lowercase, punctuation, http, equivalent-words, all, and none.
+	//                              In update mode, the normalization of the database is used.
+	//         --with-texts         stores the license texts into the database for the reports.
+	//         --bm25 <PARAMS>      specifies the parameters of bm25 stored into the database (e.g., k1=1.5,b=0.5).
+	//                              Default is k1=1.2,b=0.75, or the parameters in the database in update mode.
 	//     -h, --help               print this message.
 	// LICENSE
 	//     specifies license files.
 }
+
+func TestUpdateWithoutExtension(t *testing.T) {
+	goMain([]string{"mkliossdb", "-d", "../../update2.gz", "../../data/misc/MIT"})
+	defer os.Remove("../../update2.liossgz")
+	if status := goMain([]string{"mkliossdb", "-u", "-d", "../../update2.gz", "../../data/misc/WTFPL"}); status != 0 {
+		t.Fatalf("update of ../../update2.gz failed, status %d", status)
+	}
+	db, err := lioss.ReadDatabase("../../update2.liossgz")
+	if err != nil {
+		t.Fatalf("load failed: %s", err.Error())
+	}
+	if !db.Contains("5gram", "MIT") || !db.Contains("5gram", "WTFPL") {
+		t.Errorf("updated database did not contain both of MIT and WTFPL")
+	}
+}
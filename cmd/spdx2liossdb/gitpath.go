@@ -104,7 +104,7 @@ func readCommitID(dir string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	head, err := repository.Reference("refs/heads/master", false)
+	head, err := repository.Head()
 	if err != nil {
 		return "", err
 	}
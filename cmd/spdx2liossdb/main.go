@@ -17,16 +17,28 @@ func helpMessage(prog string) string {
 	return fmt.Sprintf(`%s [OPTIONS] <ARGUMENT>
 OPTIONS
     -d, --dest <DEST>             specifies the destination.
+    -u, --update                  updates the existing database specified by dest option.
         --with-deprecated         includes deprecated license.
         --without-deprecated      excludes deprecated license.
         --with-osi-approved       includes OSI approved licenses.
         --without-osi-approved    excludes OSI approved licenses.
+        --without-exceptions      excludes license exceptions (e.g., Classpath-exception-2.0).
+        --false-positive-rate <RATE>
+                                  specifies the target false-positive rate for calibrating the thresholds.
+                                  Default is 0.01.
+        --normalization <STEPS>   specifies the normalization steps (comma separated). Default is all.
+                                  Available values are: %s, all, and none.
+                                  In update mode, the normalization of the database is used.
+        --with-texts              stores the license texts into the database for the reports.
+        --bm25 <PARAMS>           specifies the parameters of bm25 stored into the database (e.g., k1=1.5,b=0.5).
+                                  Default is k1=%g,b=%g, or the parameters in the database in update mode.
     -v, --verbose                 verbose mode.
     -h, --help                    prints this message.
 ARGUMENT
-    the directory contains SPDX license xml files.
+    the directory contains SPDX license xml files, or
+    the json directory of SPDX license-list-data (or its parent directory).
 NOTE
-    this is the internal command, and will not be distributed to the users.`, prog)
+    this is the internal command, and will not be distributed to the users.`, prog, strings.Join(lib.NormalizeStepNames(), ", "), lioss.DefaultBM25K1, lioss.DefaultBM25B)
 }
 
 type cliOptions struct {
@@ -42,9 +54,17 @@ type withWithout struct {
 }
 
 type runtimeOptions struct {
-	verboseOpt  bool
-	osiApproved *withWithout
-	deprecated  *withWithout
+	verboseOpt        bool
+	updateOpt         bool
+	withoutExceptions bool
+	withTexts         bool
+	fpRate            float64
+	bm25              string
+	normalize         string
+	normalizeFlag     bool
+	normalizer        *lib.Normalizer
+	osiApproved       *withWithout
+	deprecated        *withWithout
 }
 
 type LicenseData struct {
@@ -52,6 +72,16 @@ type LicenseData struct {
 	content string
 }
 
+/*
+spdxSource shows the license data and the license exception data read from the SPDX license list.
+version is empty if the source does not have the version of license list (e.g., license-list-XML).
+*/
+type spdxSource struct {
+	version    string
+	licenses   []*LicenseData
+	exceptions []*LicenseData
+}
+
 func (ro *runtimeOptions) verbose(message string) {
 	if ro.verboseOpt {
 		fmt.Println(message)
@@ -100,43 +130,67 @@ func isTargetLicenseImpl(deprecated, osiApproved bool, meta *lib.LicenseMeta) bo
 	return !meta.Deprecated && !meta.OsiApproved
 }
 
+/*
+isTargetException returns true if the exception should be included into the database.
+The exceptions have no OSI approved flag, therefore, they are selected only by the deprecated flag.
+*/
+func isTargetException(opts *runtimeOptions, meta *lib.LicenseMeta) bool {
+	return !opts.withoutExceptions && meta.Deprecated == opts.deprecated.is()
+}
+
+func generateException(algo lioss.Algorithm, data *LicenseData, opts *runtimeOptions) (*lioss.License, error) {
+	if !isTargetException(opts, data.meta) {
+		return nil, fmt.Errorf("%s: not target exception", data.meta.Names.ShortName)
+	}
+	return lioss.ParseLicense(opts.normalizer, algo, strings.NewReader(data.content), data.meta.Names.ShortName)
+}
+
 func generateLicense(algo lioss.Algorithm, data *LicenseData, opts *runtimeOptions) (*lioss.License, error) {
 	if !isTargetLicense(opts, data.meta) {
 		return nil, fmt.Errorf("%s: not target license", data.meta.Names.ShortName)
 	}
-	return algo.Parse(strings.NewReader(data.content), data.meta.Names.ShortName)
+	return lioss.ParseLicense(opts.normalizer, algo, strings.NewReader(data.content), data.meta.Names.ShortName)
 }
 
-func performEachAlgorithm(db *lioss.Database, algo lioss.Algorithm, licenseData []*LicenseData, opts *runtimeOptions) error {
-	for _, data := range licenseData {
+func performEachAlgorithm(db *lioss.Database, changes *lioss.Changes, algo lioss.Algorithm, source *spdxSource, opts *runtimeOptions) error {
+	for _, data := range source.licenses {
 		license, err := generateLicense(algo, data, opts)
 		if err != nil {
 			continue
 		}
-		db.Put(algo.String(), license)
+		db.Update(algo.String(), license, changes)
 	}
-	return nil
+	for _, data := range source.exceptions {
+		exception, err := generateException(algo, data, opts)
+		if err != nil {
+			continue
+		}
+		db.UpdateException(algo.String(), exception, changes)
+	}
+	return db.StoreParameters(algo)
 }
 
-func readLicenseDatum(target string, info os.FileInfo) (*LicenseData, error) {
+type spdxReader func(path string) (*lib.LicenseMeta, string, error)
+
+func readLicenseDatum(target string, info os.FileInfo, reader spdxReader) (*LicenseData, error) {
 	if info.IsDir() {
 		return nil, fmt.Errorf("%s: is dir", info.Name())
 	}
-	meta, data, err := lib.ReadSPDX(filepath.Join(target, info.Name()))
+	meta, data, err := reader(filepath.Join(target, info.Name()))
 	if err != nil {
 		return nil, err
 	}
 	return &LicenseData{meta: meta, content: data}, nil
 }
 
-func readLicenseData(target string, opts *runtimeOptions) ([]*LicenseData, error) {
+func readLicenseData(target string, reader spdxReader) ([]*LicenseData, error) {
 	infoList, err := ioutil.ReadDir(target)
 	if err != nil {
 		return nil, err
 	}
 	results := []*LicenseData{}
 	for _, info := range infoList {
-		result, err := readLicenseDatum(target, info)
+		result, err := readLicenseDatum(target, info, reader)
 		if err == nil {
 			results = append(results, result)
 		}
@@ -144,29 +198,104 @@ func readLicenseData(target string, opts *runtimeOptions) ([]*LicenseData, error
 	return results, nil
 }
 
-func performEach(db *lioss.Database, algorithmName string, licenseData []*LicenseData, opts *runtimeOptions) error {
-	algo, err := lioss.NewAlgorithm(algorithmName)
+/*
+readExceptionData reads the license exceptions from exceptions directory in the given target (e.g., spdx/src/exceptions).
+*/
+func readExceptionData(target string) []*LicenseData {
+	results, err := readLicenseData(filepath.Join(target, "exceptions"), lib.ReadSPDXException)
+	if err != nil {
+		return []*LicenseData{}
+	}
+	return results
+}
+
+func readJSONSource(dir string) (*spdxSource, error) {
+	list, err := lib.ReadSPDXJSON(dir)
+	if err != nil {
+		return nil, err
+	}
+	source := &spdxSource{version: list.Version, licenses: toLicenseData(list.Licenses), exceptions: toLicenseData(list.Exceptions)}
+	return source, nil
+}
+
+func toLicenseData(licenses []*lib.SPDXLicense) []*LicenseData {
+	results := []*LicenseData{}
+	for _, license := range licenses {
+		results = append(results, &LicenseData{meta: license.Meta, content: license.Text})
+	}
+	return results
+}
+
+func readSource(target string, opts *runtimeOptions) (*spdxSource, error) {
+	if dir, ok := lib.FindSPDXJSONDir(target); ok {
+		opts.verbosef("read SPDX license-list-data from %s\n", dir)
+		return readJSONSource(dir)
+	}
+	licenseData, err := readLicenseData(target, lib.ReadSPDX)
+	if err != nil {
+		return nil, err
+	}
+	return &spdxSource{licenses: licenseData, exceptions: readExceptionData(target)}, nil
+}
+
+/*
+algorithmName returns the name of the given algorithm with the parameters given by the options (e.g., "bm25:k1=1.5").
+*/
+func algorithmName(name string, opts *runtimeOptions) string {
+	if name == "bm25" && opts.bm25 != "" {
+		return name + ":" + opts.bm25
+	}
+	return name
+}
+
+func performEach(db *lioss.Database, changes *lioss.Changes, name string, source *spdxSource, opts *runtimeOptions) error {
+	algo, err := lioss.NewAlgorithm(algorithmName(name, opts))
 	if err != nil {
 		return err
 	}
-	opts.verbose(algorithmName)
-	return performEachAlgorithm(db, algo, licenseData, opts)
+	opts.verbose(name)
+	return performEachAlgorithm(db, changes, algo, source, opts)
 }
 
-func performImpl(db *lioss.Database, licenseData []*LicenseData, opts *runtimeOptions) (int, error) {
+func performImpl(db *lioss.Database, changes *lioss.Changes, source *spdxSource, opts *runtimeOptions) (int, error) {
 	size := 0
 	for _, algorithmName := range lioss.AvailableAlgorithms {
-		err := performEach(db, algorithmName, licenseData, opts)
+		err := performEach(db, changes, algorithmName, source, opts)
 		if err != nil {
 			return size, err
 		}
 		size = len(db.Data[algorithmName])
 	}
+	storeLicenseData(db, source, opts)
 	return size, nil
 }
 
+/*
+storeLicenseData stores the SPDX metadata of the target licenses and exceptions into the database,
+and also their texts if withTexts option is given.
+*/
+func storeLicenseData(db *lioss.Database, source *spdxSource, opts *runtimeOptions) {
+	for _, data := range source.licenses {
+		if isTargetLicense(opts, data.meta) {
+			storeEach(db, data, opts)
+		}
+	}
+	for _, data := range source.exceptions {
+		if isTargetException(opts, data.meta) {
+			storeEach(db, data, opts)
+		}
+	}
+}
+
+func storeEach(db *lioss.Database, data *LicenseData, opts *runtimeOptions) {
+	db.PutMeta(data.meta.Names.ShortName, data.meta)
+	if opts.withTexts {
+		db.PutText(data.meta.Names.ShortName, data.content)
+	}
+}
+
 type generator interface {
-	Perform(licenseData []*LicenseData) error
+	Perform(source *spdxSource) error
 }
 
 type jsonGenerator struct {
@@ -184,29 +313,30 @@ func newGenerator(dest, from string, opts *runtimeOptions) generator {
 	if strings.HasSuffix(dest, ".json") {
 		return &jsonGenerator{dest: dest, from: from, opts: opts}
 	}
-	return &liossdbGenerator{dest: dest, opts: opts}
+	return &liossdbGenerator{dest: lioss.DestinationPath(dest), opts: opts}
 }
 
 type jsonData struct {
-	Timestamp *lioss.Time        `json:"timestamp"`
-	CommitID  string             `json:"git-commit-id"`
-	Licenses  []*lib.LicenseMeta `json:"licenses"`
+	Timestamp          *lioss.Time        `json:"timestamp"`
+	CommitID           string             `json:"git-commit-id"`
+	LicenseListVersion string             `json:"license-list-version,omitempty"`
+	Licenses           []*lib.LicenseMeta `json:"licenses"`
 }
 
-func (jg *jsonGenerator) Perform(data []*LicenseData) error {
+func (jg *jsonGenerator) Perform(source *spdxSource) error {
 	id, err := readCommitID(jg.from)
-	if err != nil {
+	if err != nil && source.version == "" {
 		fmt.Printf("readCommitID(\"%s\"): failed, %s\n", jg.from, err.Error())
 	}
-	results := &jsonData{Timestamp: lioss.Now(), CommitID: id, Licenses: []*lib.LicenseMeta{}}
-	for _, datum := range data {
+	results := &jsonData{Timestamp: lioss.Now(), CommitID: id, LicenseListVersion: source.version, Licenses: []*lib.LicenseMeta{}}
+	for _, datum := range source.licenses {
 		results.Licenses = append(results.Licenses, datum.meta)
 	}
 	return jg.writeImpl(results)
 }
 
 func (jg *jsonGenerator) writeImpl(results *jsonData) error {
-	writer, err := os.OpenFile(jg.dest, os.O_CREATE|os.O_WRONLY, 0644)
+	writer, err := os.OpenFile(jg.dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if err != nil {
 		return err
 	}
@@ -225,26 +355,53 @@ func (jg *jsonGenerator) writeImpl(results *jsonData) error {
 	return nil
 }
 
-func (ldg *liossdbGenerator) Perform(data []*LicenseData) error {
+func (ldg *liossdbGenerator) Perform(source *spdxSource) error {
 	fmt.Printf("read SPDX licenses %s-osi-approved, and %s-deprecated\n", ldg.opts.osiApproved.String(), ldg.opts.deprecated.String())
-	db := lioss.NewDatabase()
-	size, err := performImpl(db, data, ldg.opts)
+	db, err := ldg.openDatabase()
+	if err != nil {
+		return err
+	}
+	if ldg.opts.normalizer, err = db.BuildNormalizer(ldg.opts.normalize, ldg.opts.updateOpt, ldg.opts.normalizeFlag); err != nil {
+		return err
+	}
+	if source.version != "" {
+		db.LicenseListVersion = source.version
+	}
+	changes := lioss.NewChanges()
+	size, err := performImpl(db, changes, source, ldg.opts)
 	if err != nil {
 		return err
 	}
+	if !changes.IsEmpty() {
+		db.Timestamp = lioss.Now()
+	}
 	fmt.Printf("parse %d licenses for %d algorithms, and write database to %s...", size, len(db.Data), ldg.dest)
+	if err := db.CalibrateAll(ldg.opts.fpRate); err != nil {
+		return err
+	}
+	db.BuildMinHash(lioss.DefaultMinHashParameters())
 	err2 := db.WriteTo(ldg.dest)
 	fmt.Println(" done")
+	if ldg.opts.updateOpt {
+		fmt.Println(changes.String())
+	}
 	return err2
 }
 
+func (ldg *liossdbGenerator) openDatabase() (*lioss.Database, error) {
+	if ldg.opts.updateOpt {
+		return lioss.ReadDatabase(ldg.dest)
+	}
+	return lioss.NewDatabase(), nil
+}
+
 func perform(dest, target string, opts *runtimeOptions) error {
-	licenseData, err := readLicenseData(target, opts)
+	source, err := readSource(target, opts)
 	if err != nil {
 		return err
 	}
 	generator := newGenerator(dest, target, opts)
-	return generator.Perform(licenseData)
+	return generator.Perform(source)
 }
 
 func buildFlagSet(args []string) (*flag.FlagSet, *cliOptions) {
@@ -258,6 +415,12 @@ func buildFlagSet(args []string) (*flag.FlagSet, *cliOptions) {
 	flags.BoolVar(&opts.runtimeOpts.deprecated.with, "with-deprecated", false, "exclude deprecated licenses")
 	flags.BoolVar(&opts.runtimeOpts.osiApproved.with, "with-osi-approved", false, "exclude OSI approved licenses")
 	flags.BoolVarP(&opts.runtimeOpts.verboseOpt, "verbose", "v", false, "verbose mode")
+	flags.BoolVar(&opts.runtimeOpts.withoutExceptions, "without-exceptions", false, "exclude license exceptions")
+	flags.BoolVar(&opts.runtimeOpts.withTexts, "with-texts", false, "stores the license texts")
+	flags.StringVar(&opts.runtimeOpts.normalize, "normalization", "all", "specifies the normalization steps")
+	flags.StringVar(&opts.runtimeOpts.bm25, "bm25", "", "specifies the parameters of bm25")
+	flags.Float64Var(&opts.runtimeOpts.fpRate, "false-positive-rate", lioss.DefaultFalsePositiveRate, "specifies the target false-positive rate")
+	flags.BoolVarP(&opts.runtimeOpts.updateOpt, "update", "u", false, "updates the existing database")
 	flags.StringVarP(&opts.dest, "dest", "d", "default.liossdb", "specifies destination of liossdb")
 	return flags, opts
 }
@@ -286,6 +449,13 @@ func validateOptions(opts *cliOptions, flags *flag.FlagSet) (*cliOptions, error)
 	if err := validateWithAndWithout(opts.dest, opts.runtimeOpts); err != nil {
 		return nil, err
 	}
+	if opts.runtimeOpts.fpRate < 0 || opts.runtimeOpts.fpRate > 1 {
+		return nil, fmt.Errorf("%f: false-positive rate must be 0.0 to 1.0", opts.runtimeOpts.fpRate)
+	}
+	if _, err := lioss.NewAlgorithm(algorithmName("bm25", opts.runtimeOpts)); err != nil {
+		return nil, err
+	}
+	opts.runtimeOpts.normalizeFlag = flags.Changed("normalization")
 	opts.target = realArgs[0]
 	return opts, nil
 }
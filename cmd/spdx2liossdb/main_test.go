@@ -17,14 +17,26 @@ func Example_printHelp() {
 	// spdx2liossdb [OPTIONS] <ARGUMENT>
 	// OPTIONS
 	//     -d, --dest <DEST>             specifies the destination.
+	//     -u, --update                  updates the existing database specified by dest option.
 	//         --with-deprecated         includes deprecated license.
 	//         --without-deprecated      excludes deprecated license.
 	//         --with-osi-approved       includes OSI approved licenses.
 	//         --without-osi-approved    excludes OSI approved licenses.
+	//         --without-exceptions      excludes license exceptions (e.g., Classpath-exception-2.0).
+	//         --false-positive-rate <RATE>
+	//                                   specifies the target false-positive rate for calibrating the thresholds.
+	//                                   Default is 0.01.
+	//         --normalization <STEPS>   specifies the normalization steps (comma separated). Default is all.
+	//                                   Available values are: copyright, list-numbering, lowercase, punctuation, http, equivalent-words, all, and none.
+	//                                   In update mode, the normalization of the database is used.
+	//         --with-texts              stores the license texts into the database for the reports.
+	//         --bm25 <PARAMS>           specifies the parameters of bm25 stored into the database (e.g., k1=1.5,b=0.5).
+	//                                   Default is k1=1.2,b=0.75, or the parameters in the database in update mode.
 	//     -v, --verbose                 verbose mode.
 	//     -h, --help                    prints this message.
 	// ARGUMENT
-	//     the directory contains SPDX license xml files.
+	//     the directory contains SPDX license xml files, or
+	//     the json directory of SPDX license-list-data (or its parent directory).
 	// NOTE
 	//     this is the internal command, and will not be distributed to the users.
 }
@@ -85,6 +97,26 @@ func TestJson(t *testing.T) {
 	}
 }
 
+func TestJSONSource(t *testing.T) {
+	goMain([]string{"spdx2liossdb", "--with-osi-approved", "--without-deprecated", "-d", "json-source.liossdb", "../../lib/testdata"})
+	defer os.Remove("json-source.liossdb")
+	db, err := lioss.ReadDatabase("json-source.liossdb")
+	if err != nil {
+		t.Fatalf("database load error: %s", err.Error())
+	}
+	if len(db.ExceptionEntries("5gram")) != 1 {
+		t.Errorf("size of exceptions did not match, wont 1, got %d", len(db.ExceptionEntries("5gram")))
+	}
+	if db.LicenseListVersion != "3.9" {
+		t.Errorf("license list version did not match, wont 3.9, got %s", db.LicenseListVersion)
+	}
+	for key, value := range db.Data {
+		if len(value) != 2 {
+			t.Errorf("data size did not match of %s, wont 2, got %d", key, len(value))
+		}
+	}
+}
+
 func getHash(base, name string) string {
 	targetPath := filepath.Join(base, name)
 	str, _ := readString(targetPath)
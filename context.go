@@ -0,0 +1,80 @@
+package lioss
+
+import "context"
+
+/*
+ContextFinder is the optional interface of Project, which finds the license files cancellably.
+The projects over the file systems (see NewFSProject) implement this interface.
+*/
+type ContextFinder interface {
+	LicenseIDsContext(ctx context.Context) ([]string, error)
+}
+
+/*
+LicenseIDsContext returns the ids of the license files in the given project.
+If the project does not implement ContextFinder, ctx is checked only before finding the license files.
+*/
+func LicenseIDsContext(ctx context.Context, project Project) ([]string, error) {
+	if finder, ok := project.(ContextFinder); ok {
+		return finder.LicenseIDsContext(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return project.LicenseIDs(), nil
+}
+
+/*
+contextLicenseFile is an instance of LicenseFile, which fails reading after ctx is done.
+*/
+type contextLicenseFile struct {
+	ctx  context.Context
+	file LicenseFile
+}
+
+/*
+NewContextLicenseFile wraps the given license file for failing the reading by ctx.Err() after ctx is done.
+Closing the returned file closes the given file.
+*/
+func NewContextLicenseFile(ctx context.Context, file LicenseFile) LicenseFile {
+	return &contextLicenseFile{ctx: ctx, file: file}
+}
+
+/*
+ID returns the id of the license file.
+*/
+func (clf *contextLicenseFile) ID() string {
+	return clf.file.ID()
+}
+
+/*
+Read reads the content of the license file, or returns ctx.Err() if ctx is done.
+*/
+func (clf *contextLicenseFile) Read(p []byte) (int, error) {
+	if err := clf.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return clf.file.Read(p)
+}
+
+/*
+Close closes the license file.
+*/
+func (clf *contextLicenseFile) Close() error {
+	return clf.file.Close()
+}
+
+func (clf *contextLicenseFile) String() string {
+	return clf.ID()
+}
+
+/*
+contextError returns ctx.Err() if ctx is done, otherwise err.
+The errors of the cancellation are returned as is, for comparing them with context.Canceled, and context.DeadlineExceeded.
+*/
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
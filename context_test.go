@@ -0,0 +1,63 @@
+package lioss
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLicenseIDsContext(t *testing.T) {
+	testdata := []struct {
+		project Project
+		wontIDs int
+	}{
+		{NewFSProject("memory", fstest.MapFS{"LICENSE": {Data: []byte("MIT License")}, "sub/LICENSE.txt": {Data: []byte("BSD License")}}), 2},
+		{NewTextProject("-", "MIT License"), 1},
+	}
+	for _, td := range testdata {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := LicenseIDsContext(ctx, td.project); err != context.Canceled {
+			t.Errorf("%s: LicenseIDsContext with canceled context did not return context.Canceled, got %v", td.project.BasePath(), err)
+		}
+		ids, err := LicenseIDsContext(context.Background(), td.project)
+		if err != nil || len(ids) != td.wontIDs {
+			t.Errorf("%s: size of license ids did not match, wont %d, got %v (%v)", td.project.BasePath(), td.wontIDs, ids, err)
+		}
+	}
+}
+
+func TestContextLicenseFile(t *testing.T) {
+	project := NewTextProject("-", "MIT License")
+	file, _ := project.LicenseFile(TextID)
+	ctx, cancel := context.WithCancel(context.Background())
+	contextFile := NewContextLicenseFile(ctx, file)
+	defer contextFile.Close()
+
+	buffer := make([]byte, 3)
+	if _, err := contextFile.Read(buffer); err != nil || string(buffer) != "MIT" {
+		t.Errorf("read data did not match, wont \"MIT\", got \"%s\" (%v)", string(buffer), err)
+	}
+	cancel()
+	if _, err := ioutil.ReadAll(contextFile); err != context.Canceled {
+		t.Errorf("reading after cancel did not return context.Canceled, got %v", err)
+	}
+	if contextFile.ID() != TextID {
+		t.Errorf("id did not match, wont %s, got %s", TextID, contextFile.ID())
+	}
+}
+
+func TestIdentifyProjectContextCanceled(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	identifier, _ := NewIdentifier("5gram", 0.75, db)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := identifier.IdentifyProjectContext(ctx, NewFSProject("memory", fstest.MapFS{"LICENSE": {Data: []byte("MIT License")}}))
+	if result.Err != context.Canceled {
+		t.Errorf("IdentifyProjectContext with canceled context did not return context.Canceled, got %v", result.Err)
+	}
+	if _, err := identifier.IdentifyTextContext(ctx, "MIT License"); err != context.Canceled {
+		t.Errorf("IdentifyTextContext with canceled context did not return context.Canceled, got %v", err)
+	}
+}
@@ -9,14 +9,32 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/tamada/lioss/lib"
 )
 
 /*
 Database represents the database for the lioss.
+LicenseListVersion shows the version of SPDX license list which the database built from, and it is empty if unknown.
+Exceptions holds the license exceptions (e.g., Classpath-exception-2.0) built by each algorithm, as the same manner of Data.
+MinHash shows the parameters of MinHash signatures of licenses, and it is nil if the database has no signatures.
+Parameters holds the parameters of the algorithms (e.g., k1 and b of bm25), keyed by the algorithm names.
+Thresholds holds the calibrated thresholds of the algorithms (see Calibrate), keyed by the algorithm names.
+Normalization shows the version of the normalization pipeline (see lib.Normalizer) which the database was built by.
+Texts holds the canonical texts of the licenses keyed by the license names, and it is empty unless the builders are given the option (see PutText).
+Metadata holds the SPDX metadata of the licenses keyed by the license names, and it is empty for the databases not built from SPDX license list (see PutMeta).
 */
 type Database struct {
-	Timestamp *Time                 `json:"create-at"`
-	Data      map[string][]*License `json:"algorithms"`
+	Timestamp          *Time                         `json:"create-at"`
+	LicenseListVersion string                        `json:"license-list-version,omitempty"`
+	Data               map[string][]*License         `json:"algorithms"`
+	Exceptions         map[string][]*License         `json:"exceptions,omitempty"`
+	MinHash            *MinHashParameters            `json:"minhash,omitempty"`
+	Parameters         map[string]map[string]float64 `json:"parameters,omitempty"`
+	Thresholds         map[string]float64            `json:"thresholds,omitempty"`
+	Normalization      string                        `json:"normalization,omitempty"`
+	Texts              map[string]string             `json:"texts,omitempty"`
+	Metadata           map[string]*lib.LicenseMeta   `json:"metadata,omitempty"`
 }
 
 const DatabasePathEnvName = "LIOSS_DBPATH"
@@ -111,6 +129,10 @@ func loadAndMergeDB(db *Database, dir string, dbTypes DatabaseType, tp dbTypeAnd
 			fmt.Printf("%s/%s: %s\n", dir, tp.path, err.Error())
 			return db
 		}
+		if err := db.CheckNormalization(db2); err != nil {
+			fmt.Printf("%s/%s: %s\n", dir, tp.path, err.Error())
+			return db
+		}
 		db = db.Merge(db2)
 	}
 	return db
@@ -119,14 +141,96 @@ func loadAndMergeDB(db *Database, dir string, dbTypes DatabaseType, tp dbTypeAnd
 func (db *Database) Merge(other *Database) *Database {
 	newDB := NewDatabase()
 	newDB.Timestamp = db.Timestamp
-	newDB.Data = db.Data
-	for key, licenses := range other.Data {
-		orig := mergeLicense(newDB.Data[key], licenses)
-		newDB.Data[key] = orig
-	}
+	newDB.LicenseListVersion = mergeVersion(db.LicenseListVersion, other.LicenseListVersion)
+	newDB.Normalization = mergeNormalization(db, other)
+	newDB.Data = mergeData(db.Data, other.Data)
+	newDB.Exceptions = mergeData(db.Exceptions, other.Exceptions)
+	mergeMinHash(newDB, db, other)
+	newDB.Parameters = mergeParameters(db.Parameters, other.Parameters)
+	newDB.Thresholds = mergeThresholds(db.Thresholds, other.Thresholds)
+	newDB.Texts = mergeTexts(db.Texts, other.Texts)
+	newDB.Metadata = mergeMetadata(db.Metadata, other.Metadata)
 	return newDB
 }
 
+/*
+mergeThresholds merges the calibrated thresholds by choosing the higher one,
+since the merged database has more licenses, and the false-positive rate becomes higher.
+*/
+func mergeThresholds(thresholds, other map[string]float64) map[string]float64 {
+	results := map[string]float64{}
+	for _, source := range []map[string]float64{thresholds, other} {
+		for name, threshold := range source {
+			if current, ok := results[name]; !ok || current < threshold {
+				results[name] = threshold
+			}
+		}
+	}
+	return results
+}
+
+func mergeParameters(params, other map[string]map[string]float64) map[string]map[string]float64 {
+	results := map[string]map[string]float64{}
+	for _, source := range []map[string]map[string]float64{other, params} {
+		for name, values := range source {
+			results[name] = values
+		}
+	}
+	return results
+}
+
+/*
+StoreParameters stores the parameters of the given algorithm into the database, if the algorithm is Parameterized.
+The parameters given by the algorithm name (e.g., "bm25:k1=1.5") overwrite the stored ones,
+and the parameters not given are kept, since the algorithm is prepared by the database before storing.
+*/
+func (db *Database) StoreParameters(algorithm Algorithm) error {
+	parameterized, ok := algorithm.(Parameterized)
+	if !ok {
+		return nil
+	}
+	if err := algorithm.Prepare(db); err != nil {
+		return err
+	}
+	if db.Parameters == nil {
+		db.Parameters = map[string]map[string]float64{}
+	}
+	db.Parameters[algorithm.String()] = parameterized.Parameters()
+	return nil
+}
+
+/*
+mergeMinHash merges the parameters of MinHash of the given databases into newDB.
+If both databases have different parameters, the signatures are rebuilt by the parameters of db.
+*/
+func mergeMinHash(newDB, db, other *Database) {
+	if db.MinHash == nil {
+		newDB.MinHash = other.MinHash
+		return
+	}
+	newDB.MinHash = db.MinHash
+	if other.MinHash != nil && *db.MinHash != *other.MinHash {
+		newDB.BuildMinHash(db.MinHash)
+	}
+}
+
+func mergeData(data, other map[string][]*License) map[string][]*License {
+	if data == nil {
+		data = map[string][]*License{}
+	}
+	for key, licenses := range other {
+		data[key] = mergeLicense(data[key], licenses)
+	}
+	return data
+}
+
+func mergeVersion(version1, version2 string) string {
+	if version1 == "" {
+		return version2
+	}
+	return version1
+}
+
 func mergeLicense(license1, license2 []*License) []*License {
 	for _, l := range license2 {
 		found := findLicense(l, license1)
@@ -166,7 +270,7 @@ func availableDatabaseDir() (string, error) {
 NewDatabase create an instance of database for lioss.
 */
 func NewDatabase() *Database {
-	return &Database{Timestamp: Now(), Data: map[string][]*License{}}
+	return &Database{Timestamp: Now(), Data: map[string][]*License{}, Exceptions: map[string][]*License{}}
 }
 
 func (db *Database) AlgorithmCount() int {
@@ -185,20 +289,40 @@ func (db *Database) LicenseCount() int {
 	return size
 }
 
-/*WriteTo writes data in the the receiver database into the given file.*/
+/*
+WriteTo writes data in the the receiver database into the given file.
+The data is written into a temporary file in the same directory, and then the temporary file is renamed to the destination.
+Therefore, the destination file is never left in a half-written state.
+*/
 func (db *Database) WriteTo(destFile string) error {
 	dest := destination(destFile)
-	writer, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY, 0644)
+	writer, err := ioutil.TempFile(filepath.Dir(dest), ".liossdb-*")
 	if err != nil {
 		return err
 	}
-	defer writer.Close()
+	defer os.Remove(writer.Name()) // fails after rename, nothing to do.
 
 	newWriter := wrapWriter(writer, destFile)
-	err2 := db.Write(newWriter)
-	newWriter.Close() // gzip.Writer should call Close.
-	writer.Close()
-	return err2
+	err = db.Write(newWriter)
+	if newWriter != io.WriteCloser(writer) {
+		if err2 := newWriter.Close(); err == nil { // gzip.Writer should call Close.
+			err = err2
+		}
+	}
+	if err2 := writer.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		return err
+	}
+	return commitTempFile(writer.Name(), dest)
+}
+
+func commitTempFile(tempFile, dest string) error {
+	if err := os.Chmod(tempFile, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempFile, dest)
 }
 
 /*
@@ -227,6 +351,15 @@ func replaceExtension(fileName, newExt string) string {
 	return fileName[0:index] + "." + newExt
 }
 
+/*
+DestinationPath returns the path of the database file which WriteTo writes for the given path,
+e.g., "db.liossdb" for "db", and "db.liossgz" for "db.gz".
+The database builders read and write the same file in update mode by resolving the path with this function.
+*/
+func DestinationPath(dest string) string {
+	return destination(dest)
+}
+
 func destination(dest string) string {
 	if strings.HasSuffix(dest, ".liossdb") || strings.HasSuffix(dest, ".liossgz") {
 		return dest
@@ -305,7 +438,10 @@ func (db *Database) Entries(algorithmName string) []*License {
 Entry return an instance of license built by given algorithm with given license name.
 */
 func (db *Database) Entry(algoirthmName, licenseName string) *License {
-	entries := db.Entries(algoirthmName)
+	return findEntry(db.Entries(algoirthmName), licenseName)
+}
+
+func findEntry(entries []*License, licenseName string) *License {
 	for _, entry := range entries {
 		if entry.Name == licenseName {
 			return entry
@@ -328,14 +464,85 @@ func updateIfNeeded(items []*License, license *License) (updateFlag bool) {
 Put registers the given license to the database.
 */
 func (db *Database) Put(algorithmName string, license *License) {
-	items, ok := db.Data[algorithmName]
+	putLicense(db.Data, algorithmName, license)
+}
+
+func putLicense(data map[string][]*License, algorithmName string, license *License) {
+	items, ok := data[algorithmName]
 	if !ok {
 		items = []*License{}
 	}
 	if !updateIfNeeded(items, license) {
 		items = append(items, license)
 	}
-	db.Data[algorithmName] = items
+	data[algorithmName] = items
+}
+
+/*
+Update registers the given license to the database as Put, and records the change into the given changes.
+If the database has the same license data, no changes are recorded.
+*/
+func (db *Database) Update(algorithmName string, license *License, changes *Changes) {
+	updateEntry(db.Data, algorithmName, license, changes)
+}
+
+func updateEntry(data map[string][]*License, algorithmName string, license *License, changes *Changes) {
+	entry := findEntry(data[algorithmName], license.Name)
+	if entry == nil {
+		changes.add(&changes.Added, license.Name)
+	} else if !isSameFrequencies(entry.Frequencies, license.Frequencies) {
+		changes.add(&changes.Replaced, license.Name)
+	}
+	putLicense(data, algorithmName, license)
+}
+
+func isSameFrequencies(freq1, freq2 map[string]int) bool {
+	if len(freq1) != len(freq2) {
+		return false
+	}
+	for key, value := range freq1 {
+		if value2, ok := freq2[key]; !ok || value != value2 {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Remove removes the license with the given name built by the given algorithm from the database.
+This method returns true if the license was removed.
+*/
+func (db *Database) Remove(algorithmName, licenseName string) bool {
+	return removeLicense(db.Data, algorithmName, licenseName)
+}
+
+func removeLicense(data map[string][]*License, algorithmName, licenseName string) bool {
+	items := data[algorithmName]
+	for i, item := range items {
+		if item.Name == licenseName {
+			data[algorithmName] = append(items[:i:i], items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+/*
+RemoveLicense removes the license (or the license exception) with the given name from all of algorithms in the database.
+This method returns true if the license was removed from at least one algorithm.
+*/
+func (db *Database) RemoveLicense(licenseName string) bool {
+	removed := false
+	for _, data := range []map[string][]*License{db.Data, db.Exceptions} {
+		for algorithmName := range data {
+			if removeLicense(data, algorithmName, licenseName) {
+				removed = true
+			}
+		}
+	}
+	delete(db.Texts, licenseName)
+	delete(db.Metadata, licenseName)
+	return removed
 }
 
 /*
@@ -2,7 +2,10 @@ package lioss
 
 import (
 	"bytes"
+	"os"
 	"testing"
+
+	"github.com/tamada/lioss/lib"
 )
 
 func TestDatabaseTypeString(t *testing.T) {
@@ -187,3 +190,106 @@ func TestReplaceLicenseData(t *testing.T) {
 		t.Errorf("size of map did not match, wont 4, got %d", len(item.Frequencies))
 	}
 }
+
+func TestRemove(t *testing.T) {
+	db := NewDatabase()
+	db.Put("1gram", newLicense("hoge", map[string]int{"a": 1}))
+	db.Put("1gram", newLicense("fuga", map[string]int{"b": 1}))
+	db.Put("2gram", newLicense("hoge", map[string]int{"ab": 1}))
+	if db.Remove("2gram", "fuga") {
+		t.Errorf(`db.Remove("2gram", "fuga") returns true, wont false`)
+	}
+	if !db.RemoveLicense("hoge") {
+		t.Errorf(`db.RemoveLicense("hoge") returns false, wont true`)
+	}
+	if db.Contains("1gram", "hoge") || db.Contains("2gram", "hoge") {
+		t.Errorf("hoge did not removed")
+	}
+	if !db.Contains("1gram", "fuga") {
+		t.Errorf("fuga was removed")
+	}
+}
+
+func TestUpdateChanges(t *testing.T) {
+	db := NewDatabase()
+	db.Put("1gram", newLicense("hoge", map[string]int{"a": 1}))
+	db.Put("1gram", newLicense("fuga", map[string]int{"b": 1}))
+	changes := NewChanges()
+	db.Update("1gram", newLicense("hoge", map[string]int{"a": 1}), changes)
+	db.Update("1gram", newLicense("fuga", map[string]int{"b": 2}), changes)
+	db.Update("1gram", newLicense("piyo", map[string]int{"c": 1}), changes)
+	if db.RemoveLicense("hoge") {
+		changes.Remove("hoge")
+	}
+	wontString := "added: piyo\nreplaced: fuga\nremoved: hoge"
+	if changes.String() != wontString {
+		t.Errorf("changes did not match, wont %s, got %s", wontString, changes.String())
+	}
+	if !NewChanges().IsEmpty() {
+		t.Errorf("NewChanges().IsEmpty() returns false")
+	}
+}
+
+func TestWriteToTruncate(t *testing.T) {
+	dest := "testdata/truncate.liossdb"
+	defer os.Remove(dest)
+	db := NewDatabase()
+	db.Put("1gram", newLicense("hoge", map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}))
+	db.Put("1gram", newLicense("fuga", map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}))
+	if err := db.WriteTo(dest); err != nil {
+		t.Fatalf("db.WriteTo(%s) failed: %s", dest, err.Error())
+	}
+	db.RemoveLicense("fuga")
+	if err := db.WriteTo(dest); err != nil {
+		t.Fatalf("db.WriteTo(%s) failed: %s", dest, err.Error())
+	}
+	db2, err := ReadDatabase(dest)
+	if err != nil {
+		t.Fatalf("ReadDatabase(%s) failed: %s", dest, err.Error())
+	}
+	if len(db2.Entries("1gram")) != 1 {
+		t.Errorf("size of entries did not match, wont 1, got %d", len(db2.Entries("1gram")))
+	}
+}
+
+func TestTexts(t *testing.T) {
+	db := NewDatabase()
+	db.Put("1gram", newLicense("hoge", map[string]int{"a": 1}))
+	db.PutText("hoge", "hoge license")
+	if text, ok := db.Text("hoge"); !ok || text != "hoge license" {
+		t.Errorf(`db.Text("hoge") did not match, wont "hoge license", got "%s" (%v)`, text, ok)
+	}
+	other := NewDatabase()
+	other.PutText("hoge", "other hoge license")
+	other.PutText("fuga", "fuga license")
+	merged := db.Merge(other)
+	if text, _ := merged.Text("hoge"); text != "hoge license" {
+		t.Errorf(`merged text of hoge did not match, wont "hoge license", got "%s"`, text)
+	}
+	if _, ok := merged.Text("fuga"); !ok {
+		t.Errorf("merged database did not have the text of fuga")
+	}
+	db.RemoveLicense("hoge")
+	if _, ok := db.Text("hoge"); ok {
+		t.Errorf("text of hoge was not removed")
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	db := NewDatabase()
+	db.Put("1gram", newLicense("hoge", map[string]int{"a": 1}))
+	db.PutMeta("hoge", &lib.LicenseMeta{Names: &lib.Names{ShortName: "hoge", FullName: "Hoge License"}, OsiApproved: true})
+	other := NewDatabase()
+	other.PutMeta("fuga", &lib.LicenseMeta{Names: &lib.Names{ShortName: "fuga", FullName: "Fuga License"}})
+	merged := db.Merge(other)
+	if meta := merged.Meta("hoge"); meta == nil || !meta.OsiApproved {
+		t.Errorf("merged metadata of hoge did not match, got %v", meta)
+	}
+	if merged.Meta("fuga") == nil || merged.Meta("piyo") != nil {
+		t.Errorf("merged metadata did not match")
+	}
+	db.RemoveLicense("hoge")
+	if db.Meta("hoge") != nil {
+		t.Errorf("metadata of hoge was not removed")
+	}
+}
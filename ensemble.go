@@ -0,0 +1,307 @@
+package lioss
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+/*
+DefaultVoteThreshold is the default threshold of each algorithm for voting in the ensemble algorithm.
+*/
+const DefaultVoteThreshold = 0.75
+
+/*
+EntriesProvider is implemented by the algorithms whose entries are not stored in the database by their names (e.g., ensemble).
+Such algorithms build their entries from the entries of other algorithms.
+*/
+type EntriesProvider interface {
+	Entries(db *Database) []*License
+	ExceptionEntries(db *Database) []*License
+}
+
+/*
+DetailedComparator is implemented by the algorithms which can report the breakdown of the similarity (e.g., ensemble).
+*/
+type DetailedComparator interface {
+	CompareDetails(license1, license2 *License) (float64, map[string]float64)
+}
+
+type ensembleMember struct {
+	algorithm Algorithm
+	weight    float64
+}
+
+/*
+ensemble is an implementation type of Algorithm, which combines the similarities of several algorithms.
+The similarity is the weighted average of similarities of the members in default,
+or the weighted ratio of the members voting for the license (the similarity is higher than the vote threshold) in the vote mode.
+*/
+type ensemble struct {
+	name          string
+	members       []*ensembleMember
+	voteMode      bool
+	voteThreshold float64
+	db            *Database
+	entries       []*License
+	exceptions    []*License
+}
+
+/*
+createEnsembleAlgorithm creates the ensemble algorithm from the given name,
+such as "ensemble:5gram,tfidf,wordfreq", "ensemble:5gram=2,tfidf=1", and "ensemble:5gram,tfidf,wordfreq,mode=vote,vote=0.8".
+*/
+func createEnsembleAlgorithm(name string) (Algorithm, error) {
+	items := strings.SplitN(strings.ToLower(name), ":", 2)
+	if items[0] != "ensemble" || len(items) != 2 || items[1] == "" {
+		return nil, fmt.Errorf("%s: ensemble requires algorithms, e.g., ensemble:5gram,tfidf", name)
+	}
+	ensemble := &ensemble{members: []*ensembleMember{}, voteThreshold: DefaultVoteThreshold}
+	for _, param := range strings.Split(items[1], ",") {
+		if err := ensemble.parseParameter(param); err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err.Error())
+		}
+	}
+	if len(ensemble.members) == 0 {
+		return nil, fmt.Errorf("%s: ensemble requires algorithms", name)
+	}
+	ensemble.name = ensemble.canonicalName()
+	return ensemble, nil
+}
+
+/*
+canonicalName returns the name of the ensemble in the canonical form, for treating the same ensembles as the same name.
+The weights are omitted if they are 1, and the vote threshold is shown only if it is not the default value,
+e.g., "ensemble:5gram=2.0,tfidf=1,mode=vote" is "ensemble:5gram=2,tfidf,mode=vote".
+*/
+func (ensemble *ensemble) canonicalName() string {
+	params := []string{}
+	for _, member := range ensemble.members {
+		if member.weight == 1 {
+			params = append(params, member.algorithm.String())
+		} else {
+			params = append(params, fmt.Sprintf("%s=%s", member.algorithm.String(), strconv.FormatFloat(member.weight, 'g', -1, 64)))
+		}
+	}
+	if ensemble.voteMode && ensemble.voteThreshold == DefaultVoteThreshold {
+		params = append(params, "mode=vote")
+	} else if ensemble.voteMode {
+		params = append(params, "vote="+strconv.FormatFloat(ensemble.voteThreshold, 'g', -1, 64))
+	}
+	return "ensemble:" + strings.Join(params, ",")
+}
+
+func (ensemble *ensemble) parseParameter(param string) error {
+	keyValue := strings.SplitN(param, "=", 2)
+	switch keyValue[0] {
+	case "mode":
+		return ensemble.parseMode(keyValue)
+	case "vote":
+		return ensemble.parseVote(keyValue)
+	}
+	return ensemble.parseMember(keyValue)
+}
+
+func (ensemble *ensemble) parseMode(keyValue []string) error {
+	if len(keyValue) != 2 || (keyValue[1] != "vote" && keyValue[1] != "weight") {
+		return fmt.Errorf("%s: mode must be vote or weight", strings.Join(keyValue, "="))
+	}
+	ensemble.voteMode = keyValue[1] == "vote"
+	return nil
+}
+
+func (ensemble *ensemble) parseVote(keyValue []string) error {
+	value, err := parseValue(keyValue)
+	if err != nil || value > 1 {
+		return fmt.Errorf("%s: vote must be 0.0 to 1.0", strings.Join(keyValue, "="))
+	}
+	ensemble.voteMode = true
+	ensemble.voteThreshold = value
+	return nil
+}
+
+func (ensemble *ensemble) parseMember(keyValue []string) error {
+	if strings.HasPrefix(keyValue[0], "ensemble") {
+		return fmt.Errorf("ensemble cannot contain ensemble")
+	}
+	algorithm, err := NewAlgorithm(keyValue[0])
+	if err != nil {
+		return err
+	}
+	weight := 1.0
+	if len(keyValue) == 2 {
+		if weight, err = parseValue(keyValue); err != nil {
+			return fmt.Errorf("%s: invalid weight", strings.Join(keyValue, "="))
+		}
+	}
+	ensemble.members = append(ensemble.members, &ensembleMember{algorithm: algorithm, weight: weight})
+	return nil
+}
+
+func parseValue(keyValue []string) (float64, error) {
+	if len(keyValue) != 2 {
+		return 0, fmt.Errorf("%s: value not found", keyValue[0])
+	}
+	value, err := strconv.ParseFloat(keyValue[1], 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%s: invalid value", keyValue[1])
+	}
+	return value, nil
+}
+
+func (ensemble *ensemble) String() string {
+	return ensemble.name
+}
+
+/*
+Indexable of ensemble returns true if all of the members are indexable.
+*/
+func (ensemble *ensemble) Indexable() bool {
+	for _, member := range ensemble.members {
+		if !isIndexable(member.algorithm) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Prepare of ensemble prepares all of the members, and builds the composite entries from the entries of the members.
+*/
+func (ensemble *ensemble) Prepare(db *Database) error {
+	for _, member := range ensemble.members {
+		if err := member.algorithm.Prepare(db); err != nil {
+			return err
+		}
+	}
+	ensemble.db = db
+	ensemble.entries = ensemble.buildComposites(db.Entries)
+	ensemble.exceptions = ensemble.buildComposites(db.ExceptionEntries)
+	return nil
+}
+
+/*
+buildComposites builds the composite licenses which hold the licenses of all of the members.
+The frequencies, and the signature of the composite license are those of the first member,
+therefore, the MinHash index and the exception detection work by the first member.
+*/
+func (ensemble *ensemble) buildComposites(entries func(algorithmName string) []*License) []*License {
+	memberEntries := []map[string]*License{}
+	for _, member := range ensemble.members {
+		memberEntries = append(memberEntries, licenseMap(entries(member.algorithm.String())))
+	}
+	results := []*License{}
+	for _, primary := range entries(ensemble.members[0].algorithm.String()) {
+		if composite := ensemble.buildComposite(primary, memberEntries); composite != nil {
+			results = append(results, composite)
+		}
+	}
+	return results
+}
+
+func (ensemble *ensemble) buildComposite(primary *License, memberEntries []map[string]*License) *License {
+	composite := &License{Name: primary.Name, Frequencies: primary.Frequencies, Signature: primary.Signature, members: map[string]*License{}}
+	for i, member := range ensemble.members {
+		license, ok := memberEntries[i][primary.Name]
+		if !ok {
+			return nil
+		}
+		composite.members[member.algorithm.String()] = license
+	}
+	return composite
+}
+
+func licenseMap(licenses []*License) map[string]*License {
+	results := map[string]*License{}
+	for _, license := range licenses {
+		results[license.Name] = license
+	}
+	return results
+}
+
+/*
+Entries returns the composite licenses built in Prepare.
+*/
+func (ensemble *ensemble) Entries(db *Database) []*License {
+	if ensemble.db != db {
+		return ensemble.buildComposites(db.Entries)
+	}
+	return ensemble.entries
+}
+
+/*
+ExceptionEntries returns the composite license exceptions built in Prepare.
+*/
+func (ensemble *ensemble) ExceptionEntries(db *Database) []*License {
+	if ensemble.db != db {
+		return ensemble.buildComposites(db.ExceptionEntries)
+	}
+	return ensemble.exceptions
+}
+
+/*
+Parse parses given data by all of the members, and creates the composite license.
+*/
+func (ensemble *ensemble) Parse(reader io.Reader, licenseName string) (*License, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	composite := &License{Name: licenseName, members: map[string]*License{}}
+	for _, member := range ensemble.members {
+		license, err := member.algorithm.Parse(bytes.NewReader(data), licenseName)
+		if err != nil {
+			return nil, err
+		}
+		composite.members[member.algorithm.String()] = license
+	}
+	composite.Frequencies = composite.members[ensemble.members[0].algorithm.String()].Frequencies
+	return composite, nil
+}
+
+/*
+Compare computes the combined similarity between given two licenses.
+*/
+func (ensemble *ensemble) Compare(license1, license2 *License) float64 {
+	similarity, _ := ensemble.CompareDetails(license1, license2)
+	return similarity
+}
+
+/*
+CompareDetails computes the combined similarity between given two licenses, and the similarities of each member.
+*/
+func (ensemble *ensemble) CompareDetails(license1, license2 *License) (float64, map[string]float64) {
+	breakdown := map[string]float64{}
+	sum, totalWeight := 0.0, 0.0
+	for _, member := range ensemble.members {
+		name := member.algorithm.String()
+		similarity := compareMember(member.algorithm, license1.members[name], license2.members[name])
+		breakdown[name] = similarity
+		sum += member.weight * ensemble.score(similarity)
+		totalWeight += member.weight
+	}
+	if totalWeight == 0 {
+		return 0, breakdown
+	}
+	return sum / totalWeight, breakdown
+}
+
+func (ensemble *ensemble) score(similarity float64) float64 {
+	if !ensemble.voteMode {
+		return similarity
+	}
+	if similarity >= ensemble.voteThreshold {
+		return 1
+	}
+	return 0
+}
+
+func compareMember(algorithm Algorithm, license1, license2 *License) float64 {
+	if license1 == nil || license2 == nil {
+		return 0
+	}
+	return algorithm.Compare(license1, license2)
+}
@@ -0,0 +1,122 @@
+package lioss
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestCreateEnsemble(t *testing.T) {
+	testdata := []struct {
+		giveString   string
+		successFlag  bool
+		wontMembers  int
+		wontVoteMode bool
+	}{
+		{"ensemble:5gram,tfidf,wordfreq", true, 3, false},
+		{"ensemble:5gram=2,tfidf", true, 2, false},
+		{"ensemble:5gram,tfidf,mode=vote", true, 2, true},
+		{"ensemble:5gram,tfidf,vote=0.8", true, 2, true},
+		{"ensemble", false, 0, false},
+		{"ensemble:", false, 0, false},
+		{"ensemble:mode=vote", false, 0, false},
+		{"ensemble:5gram,unknown", false, 0, false},
+		{"ensemble:5gram=-1", false, 0, false},
+		{"ensemble:5gram,vote=1.5", false, 0, false},
+		{"ensemble:5gram,mode=average", false, 0, false},
+	}
+	for _, td := range testdata {
+		algorithm, err := NewAlgorithm(td.giveString)
+		if (err == nil) != td.successFlag {
+			t.Errorf("%s: success flag did not match, wont %v, got %v", td.giveString, td.successFlag, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		ensemble := algorithm.(*ensemble)
+		if len(ensemble.members) != td.wontMembers || ensemble.voteMode != td.wontVoteMode {
+			t.Errorf("%s: did not match, wont %d members (vote: %v), got %d members (vote: %v)", td.giveString, td.wontMembers, td.wontVoteMode, len(ensemble.members), ensemble.voteMode)
+		}
+		if algorithm.String() != td.giveString {
+			t.Errorf("%s: name did not match, got %s", td.giveString, algorithm.String())
+		}
+	}
+}
+
+func buildEnsembleTestDB(t *testing.T, algorithmNames []string) *Database {
+	db := NewDatabase()
+	for _, name := range algorithmNames {
+		algorithm, _ := NewAlgorithm(name)
+		for _, license := range buildMinHashTestDB(t, algorithm).Entries(name) {
+			db.Put(name, license)
+		}
+	}
+	db.BuildMinHash(DefaultMinHashParameters())
+	return db
+}
+
+func TestIdentifyByEnsemble(t *testing.T) {
+	testdata := []struct {
+		algorithm       string
+		wontProbability float64
+	}{
+		{"ensemble:5gram,tfidf,wordfreq", 1.0},
+		{"ensemble:5gram=3,wordfreq,mode=vote", 1.0},
+	}
+	db := buildEnsembleTestDB(t, []string{"5gram", "tfidf", "wordfreq"})
+	for _, td := range testdata {
+		identifier, err := NewIdentifier(td.algorithm, 0.75, db)
+		if err != nil {
+			t.Fatalf("%s: %s", td.algorithm, err.Error())
+		}
+		if len(identifier.Entries()) != len(db.Entries("5gram")) {
+			t.Errorf("%s: entries size did not match, wont %d, got %d", td.algorithm, len(db.Entries("5gram")), len(identifier.Entries()))
+		}
+		reader, _ := os.Open("data/misc/MIT")
+		license, _ := identifier.Comparator.Parse(reader, "LICENSE")
+		reader.Close()
+		results, _ := identifier.identify(context.Background(), license)
+		if len(results) == 0 || results[0].Name != "MIT" {
+			t.Errorf("%s: top result did not match, wont MIT, got %v", td.algorithm, results)
+			continue
+		}
+		if results[0].Probability < td.wontProbability-1e-9 {
+			t.Errorf("%s: probability did not match, wont %f, got %f", td.algorithm, td.wontProbability, results[0].Probability)
+		}
+		if len(results[0].Breakdown) != len(identifier.Comparator.(*ensemble).members) {
+			t.Errorf("%s: breakdown did not match, got %v", td.algorithm, results[0].Breakdown)
+		}
+	}
+}
+
+func TestEnsembleWeight(t *testing.T) {
+	algorithm, _ := NewAlgorithm("ensemble:1gram=3,2gram=1")
+	license1 := &License{Name: "l1", members: map[string]*License{"1gram": newLicense("l1", map[string]int{"a": 1}), "2gram": newLicense("l1", map[string]int{"ab": 1})}}
+	license2 := &License{Name: "l2", members: map[string]*License{"1gram": newLicense("l2", map[string]int{"a": 1}), "2gram": newLicense("l2", map[string]int{"cd": 1})}}
+	if got := algorithm.Compare(license1, license2); got < 0.75-1e-9 || got > 0.75+1e-9 {
+		t.Errorf("weighted similarity did not match, wont 0.75, got %f", got)
+	}
+}
+
+func TestEnsembleName(t *testing.T) {
+	testdata := []struct {
+		giveString string
+		wontName   string
+	}{
+		{"ensemble:5gram,tfidf", "ensemble:5gram,tfidf"},
+		{"ENSEMBLE:5gram=1.0,TFIDF=2.0", "ensemble:5gram,tfidf=2"},
+		{"ensemble:5gram,tfidf,vote=0.75", "ensemble:5gram,tfidf,mode=vote"},
+		{"ensemble:5gram,tfidf,mode=vote,vote=0.8", "ensemble:5gram,tfidf,vote=0.8"},
+	}
+	for _, td := range testdata {
+		algorithm, err := NewAlgorithm(td.giveString)
+		if err != nil {
+			t.Errorf("%s: NewAlgorithm failed: %s", td.giveString, err.Error())
+			continue
+		}
+		if algorithm.String() != td.wontName {
+			t.Errorf("%s: name did not match, wont %s, got %s", td.giveString, td.wontName, algorithm.String())
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package lioss
+
+/*
+DefaultExceptionThreshold is the default threshold for detecting license exceptions.
+The exception texts are short and consist of the common terms of license texts,
+therefore, the threshold must be higher than that of licenses.
+*/
+const DefaultExceptionThreshold = 0.95
+
+/*
+ExceptionEntries returns a slice of license exceptions built by given algorithm.
+*/
+func (db *Database) ExceptionEntries(algorithmName string) []*License {
+	return db.Exceptions[algorithmName]
+}
+
+/*
+PutException registers the given license exception to the database.
+*/
+func (db *Database) PutException(algorithmName string, exception *License) {
+	putLicense(db.Exceptions, algorithmName, exception)
+}
+
+/*
+UpdateException registers the given license exception to the database as PutException, and records the change into the given changes.
+*/
+func (db *Database) UpdateException(algorithmName string, exception *License, changes *Changes) {
+	updateEntry(db.Exceptions, algorithmName, exception, changes)
+}
+
+/*
+identifyException finds the most probable license exception contained in the given license.
+The license files having an exception contain both of the license text and the exception text,
+therefore, the probability is calculated by the containment of exception in the license.
+This function returns nil if no exceptions are found.
+*/
+func (identifier *Identifier) identifyException(license *License) *Result {
+	var found *Result
+	for _, exception := range identifier.ExceptionEntries() {
+		probability := license.containment(exception)
+		if probability >= identifier.ExceptionThreshold && (found == nil || found.Probability < probability) {
+			found = &Result{Name: exception.Name, Probability: probability}
+		}
+	}
+	return found
+}
+
+/*
+attachException attaches the given exception to the most probable result only,
+since the exception is found in the license file, not in each candidate license.
+The results must be sorted by the probabilities.
+*/
+func attachException(results []*Result, exception *Result) []*Result {
+	if exception == nil || len(results) == 0 {
+		return results
+	}
+	results[0].Exception = exception.Name
+	results[0].ExceptionProbability = exception.Probability
+	return results
+}
@@ -0,0 +1,79 @@
+package lioss
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+)
+
+func buildExceptionTestDB(t *testing.T, algorithm Algorithm) *Database {
+	db := NewDatabase()
+	for _, name := range []string{"GPLv2.0", "MIT"} {
+		reader, _ := os.Open("data/misc/" + name)
+		license, err := algorithm.Parse(reader, name)
+		reader.Close()
+		if err != nil {
+			t.Fatalf("parse %s failed: %s", name, err.Error())
+		}
+		db.Put(algorithm.String(), license)
+	}
+	reader, _ := os.Open("testdata/exceptions/Classpath-exception-2.0")
+	defer reader.Close()
+	exception, _ := algorithm.Parse(reader, "Classpath-exception-2.0")
+	db.PutException(algorithm.String(), exception)
+	return db
+}
+
+func TestIdentifyException(t *testing.T) {
+	testdata := []struct {
+		algorithm      string
+		paths          []string
+		wontExpression string
+	}{
+		{"5gram", []string{"data/misc/GPLv2.0", "testdata/exceptions/Classpath-exception-2.0"}, "GPLv2.0 WITH Classpath-exception-2.0"},
+		{"5gram", []string{"data/misc/GPLv2.0"}, "GPLv2.0"},
+		{"wordfreq", []string{"data/misc/GPLv2.0", "testdata/exceptions/Classpath-exception-2.0"}, "GPLv2.0 WITH Classpath-exception-2.0"},
+		{"wordfreq", []string{"data/misc/GPLv2.0"}, "GPLv2.0"},
+	}
+	for _, td := range testdata {
+		algorithm, _ := NewAlgorithm(td.algorithm)
+		identifier, _ := NewIdentifier(td.algorithm, 0.75, buildExceptionTestDB(t, algorithm))
+		readers := []io.Reader{}
+		for _, path := range td.paths {
+			file, _ := os.Open(path)
+			defer file.Close()
+			readers = append(readers, file)
+		}
+		license, _ := identifier.Comparator.Parse(io.MultiReader(readers...), "LICENSE")
+		results, _ := identifier.identify(context.Background(), license)
+		if len(results) == 0 || results[0].Expression() != td.wontExpression {
+			t.Errorf("identify(%v) by %s did not match, wont %s, got %v", td.paths, td.algorithm, td.wontExpression, results)
+		}
+	}
+}
+
+func TestRemoveLicenseWithException(t *testing.T) {
+	db := NewDatabase()
+	db.PutException("1gram", newLicense("Classpath-exception-2.0", map[string]int{"a": 1}))
+	if !db.RemoveLicense("Classpath-exception-2.0") {
+		t.Errorf("RemoveLicense did not remove the exception")
+	}
+	if len(db.ExceptionEntries("1gram")) != 0 {
+		t.Errorf("exception was not removed")
+	}
+}
+
+func TestAttachException(t *testing.T) {
+	results := []*Result{{Name: "GPL-2.0", Probability: 0.98}, {Name: "MIT", Probability: 0.80}}
+	results = attachException(results, &Result{Name: "Classpath-exception-2.0", Probability: 0.99})
+	wontExpressions := []string{"GPL-2.0 WITH Classpath-exception-2.0", "MIT"}
+	for i, result := range results {
+		if result.Expression() != wontExpressions[i] {
+			t.Errorf("results[%d] did not match, wont %s, got %s", i, wontExpressions[i], result.Expression())
+		}
+	}
+	if got := attachException([]*Result{}, &Result{Name: "Classpath-exception-2.0"}); len(got) != 0 {
+		t.Errorf("attachException to no results, wont empty, got %v", got)
+	}
+}
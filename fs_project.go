@@ -0,0 +1,190 @@
+package lioss
+
+import (
+	"context"
+	"io"
+	"io/fs"
+)
+
+/*
+fsProject is an instance of Project over fs.FS.
+The file system is opened by opener, and the files in it are found, at the first call of the methods.
+*/
+type fsProject struct {
+	basePath     string
+	opener       func() (fs.FS, io.Closer, error)
+	fsys         fs.FS
+	closer       io.Closer
+	found        bool
+	openErr      error
+	licensePaths []string
+	noticePaths  []string
+	grantPaths   []string
+}
+
+/*
+NewFSProject creates an instance of Project over the given file system (e.g., embed.FS, fstest.MapFS, and zip.Reader).
+basePath is used only for showing the project.
+The returned project also implements NoticeFinder, GrantFinder, and ContextFinder.
+*/
+func NewFSProject(basePath string, fsys fs.FS) Project {
+	return newFSProject(basePath, func() (fs.FS, io.Closer, error) {
+		return fsys, nil, nil
+	})
+}
+
+func newFSProject(basePath string, opener func() (fs.FS, io.Closer, error)) *fsProject {
+	return &fsProject{basePath: basePath, opener: opener, licensePaths: []string{}, noticePaths: []string{}, grantPaths: []string{}}
+}
+
+/*
+BasePath returns the path of the project.
+*/
+func (project *fsProject) BasePath() string {
+	return project.basePath
+}
+
+/*
+Close closes project.
+*/
+func (project *fsProject) Close() error {
+	if project.closer != nil {
+		return project.closer.Close()
+	}
+	return nil
+}
+
+/*
+LicenseIDs returns ids containing the project for LicenseFile method.
+*/
+func (project *fsProject) LicenseIDs() []string {
+	project.find(context.Background())
+	return project.licensePaths
+}
+
+/*
+LicenseIDsContext returns ids containing the project, and stops finding the files when ctx is done.
+The project finds the files again at the next call, if the finding is stopped.
+The error is also returned if the file system of the project cannot be opened (e.g., the corrupted zip file).
+*/
+func (project *fsProject) LicenseIDsContext(ctx context.Context) ([]string, error) {
+	if err := project.find(ctx); err != nil {
+		return nil, err
+	}
+	return project.licensePaths, nil
+}
+
+/*
+LicenseFile opens the license file of the given id.
+*/
+func (project *fsProject) LicenseFile(licenseID string) (LicenseFile, error) {
+	if err := project.open(); err != nil {
+		return nil, err
+	}
+	file, err := project.fsys.Open(licenseID)
+	if err != nil {
+		return nil, err
+	}
+	return &basicLicenseFile{id: licenseID, reader: file}, nil
+}
+
+/*
+NoticeIDs returns ids of NOTICE files in the project for NoticeFile method.
+*/
+func (project *fsProject) NoticeIDs() []string {
+	project.find(context.Background())
+	return project.noticePaths
+}
+
+/*
+NoticeFile opens the NOTICE file of the given id.
+*/
+func (project *fsProject) NoticeFile(noticeID string) (LicenseFile, error) {
+	return project.LicenseFile(noticeID)
+}
+
+/*
+GrantIDs returns ids of README files, and the source files in the project for GrantFile method.
+*/
+func (project *fsProject) GrantIDs() []string {
+	project.find(context.Background())
+	return project.grantPaths
+}
+
+/*
+GrantFile opens the file of the given id.
+*/
+func (project *fsProject) GrantFile(grantID string) (LicenseFile, error) {
+	return project.LicenseFile(grantID)
+}
+
+func (project *fsProject) open() error {
+	if project.fsys != nil {
+		return nil
+	}
+	fsys, closer, err := project.opener()
+	if err != nil {
+		return err
+	}
+	project.fsys, project.closer = fsys, closer
+	return nil
+}
+
+/*
+find finds the license files, NOTICE files, and the files for the version grant statements in the project.
+If the file system cannot be opened, the project has no files, and the error is returned at every call.
+The unreadable directories and files are skipped, not to drop the files found after them.
+*/
+func (project *fsProject) find(ctx context.Context) error {
+	if project.found {
+		return project.openErr
+	}
+	if err := project.open(); err != nil {
+		project.found, project.openErr = true, err
+		return err
+	}
+	licensePaths, noticePaths, grantPaths := []string{}, []string{}, []string{}
+	err := fs.WalkDir(project.fsys, ".", func(path string, entry fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			return skipUnreadable(path, entry, err)
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if isLicenseFile(path) {
+			licensePaths = append(licensePaths, path)
+		}
+		if isNoticeFile(path) {
+			noticePaths = append(noticePaths, path)
+		}
+		if isGrantFile(path) {
+			grantPaths = append(grantPaths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sortByLength(licensePaths)
+	sortByLength(noticePaths)
+	sortByLength(grantPaths)
+	project.found = true
+	project.licensePaths, project.noticePaths, project.grantPaths = licensePaths, noticePaths, grantPaths
+	return nil
+}
+
+/*
+skipUnreadable skips the unreadable entry in fs.WalkDir, and returns the error only if the root of the walk is unreadable.
+*/
+func skipUnreadable(path string, entry fs.DirEntry, err error) error {
+	if path == "." {
+		return err
+	}
+	if entry != nil && entry.IsDir() {
+		return fs.SkipDir
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package lioss
+
+import (
+	"context"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFSProject(t *testing.T) {
+	fsys := fstest.MapFS{
+		"LICENSE":                   {Data: []byte("MIT License")},
+		"NOTICE":                    {Data: []byte("Copyright 2020 lioss")},
+		"README.md":                 {Data: []byte("# lioss")},
+		"src/main.go":               {Data: []byte("package main")},
+		"src/data.json":             {Data: []byte("{}")},
+		"vendor/sub/LICENSE.txt":    {Data: []byte("BSD License")},
+		"vendor/sub/docs/guide.txt": {Data: []byte("guide")},
+	}
+	project := NewFSProject("memory", fsys)
+	defer project.Close()
+
+	if project.BasePath() != "memory" {
+		t.Errorf("base path did not match, wont memory, got %s", project.BasePath())
+	}
+	testdata := []struct {
+		name    string
+		gotIDs  []string
+		wontIDs []string
+	}{
+		{"license", project.LicenseIDs(), []string{"LICENSE", "vendor/sub/LICENSE.txt"}},
+		{"notice", project.(NoticeFinder).NoticeIDs(), []string{"NOTICE"}},
+		{"grant", project.(GrantFinder).GrantIDs(), []string{"README.md", "src/main.go"}},
+	}
+	for _, td := range testdata {
+		if len(td.gotIDs) != len(td.wontIDs) {
+			t.Errorf("%s: ids did not match, wont %v, got %v", td.name, td.wontIDs, td.gotIDs)
+			continue
+		}
+		for i, id := range td.wontIDs {
+			if td.gotIDs[i] != id {
+				t.Errorf("%s: ids[%d] did not match, wont %s, got %s", td.name, i, id, td.gotIDs[i])
+			}
+		}
+	}
+}
+
+func TestFSProjectLicenseFile(t *testing.T) {
+	project := NewFSProject("memory", fstest.MapFS{"LICENSE": {Data: []byte("MIT License")}})
+	defer project.Close()
+
+	file, err := project.LicenseFile("LICENSE")
+	if err != nil {
+		t.Fatalf("LICENSE: license file open error: %s", err.Error())
+	}
+	defer file.Close()
+	data, _ := ioutil.ReadAll(file)
+	if file.ID() != "LICENSE" || string(data) != "MIT License" {
+		t.Errorf("LICENSE: license file did not match, got %s (%s)", file.ID(), string(data))
+	}
+	if _, err := project.LicenseFile("not/existing/file"); err == nil {
+		t.Errorf("not/existing/file: found, wont not found")
+	}
+}
+
+/*
+unreadableFS is fs.FS which fails reading the given directory.
+*/
+type unreadableFS struct {
+	fstest.MapFS
+	dir string
+}
+
+func (fsys *unreadableFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name == fsys.dir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrPermission}
+	}
+	return fsys.MapFS.ReadDir(name)
+}
+
+func TestFSProjectWithUnreadableDirectory(t *testing.T) {
+	fsys := &unreadableFS{MapFS: fstest.MapFS{
+		"LICENSE":           {Data: []byte("MIT License")},
+		"a/secret/LICENSE":  {Data: []byte("BSD License")},
+		"z/vendor/LICENSE":  {Data: []byte("Apache License")},
+		"z/vendor/README":   {Data: []byte("readme")},
+		"z/vendor/NOTICE":   {Data: []byte("notice")},
+		"z/vendor/src/a.go": {Data: []byte("package a")},
+	}, dir: "a/secret"}
+	project := NewFSProject("memory", fsys)
+	ids, err := project.(ContextFinder).LicenseIDsContext(context.Background())
+	if err != nil || len(ids) != 2 || ids[0] != "LICENSE" || ids[1] != "z/vendor/LICENSE" {
+		t.Errorf("license ids did not match, wont [LICENSE z/vendor/LICENSE], got %v (%v)", ids, err)
+	}
+}
+
+func TestFSProjectWithCorruptedZip(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "lioss")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "broken.zip")
+	ioutil.WriteFile(path, []byte("PK\x03\x04 broken"), 0644)
+	project := newZipProject(path)
+	defer project.Close()
+	if _, err := project.LicenseIDsContext(context.Background()); err == nil {
+		t.Errorf("%s: LicenseIDsContext should be failed", path)
+	}
+	db := NewDatabase()
+	db.Put("5gram", newLicense("MIT", map[string]int{"mit l": 1}))
+	identifier, _ := NewIdentifier("5gram", 0.75, db)
+	if result := identifier.IdentifyProject(project); result.Err == nil {
+		t.Errorf("%s: the error of the project did not match, got %v", path, result.Err)
+	}
+}
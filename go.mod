@@ -1,6 +1,6 @@
 module github.com/tamada/lioss
 
-go 1.14
+go 1.16
 
 require (
 	github.com/denisbrodbeck/striphtmltags v6.6.6+incompatible
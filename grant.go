@@ -0,0 +1,321 @@
+package lioss
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strings"
+)
+
+/*
+GrantStatus shows whether the GNU license is granted for the specific version only, or for the version or any later version.
+*/
+type GrantStatus string
+
+const (
+	/*GrantOnly shows the license is granted for the specific version only (e.g., GPL-2.0-only).*/
+	GrantOnly GrantStatus = "only"
+	/*GrantOrLater shows the license is granted for the version or any later version (e.g., GPL-2.0-or-later).*/
+	GrantOrLater GrantStatus = "or-later"
+	/*GrantUndetermined shows no statements, or the conflicting statements are found in the project.*/
+	GrantUndetermined GrantStatus = "undetermined"
+)
+
+/*
+Grant shows the version grant of a GNU license (GPL, LGPL, and AGPL), determined by the statements in the project.
+The texts of the GNU licenses are the same for the -only and the -or-later variants, and the difference lies in the notices of the projects,
+such as "either version 2 of the License, or (at your option) any later version".
+Source and Statement show the file and the statement determined the status, and they are empty if the status is undetermined.
+*/
+type Grant struct {
+	Status    GrantStatus `json:"status"`
+	Source    string      `json:"source,omitempty"`
+	Statement string      `json:"statement,omitempty"`
+}
+
+/*
+GrantFinder is an optional interface of Project for finding the files which may contain the version grant statements of the GNU licenses,
+such as README files, and the source files.
+*/
+type GrantFinder interface {
+	/* GrantIDs returns the ids for the files which may contain the version grant statements. */
+	GrantIDs() []string
+	/* GrantFile returns the file of the given id. */
+	GrantFile(grantID string) (LicenseFile, error)
+}
+
+/*
+gnuLicense shows the family (GPL, LGPL, or AGPL) and the version (e.g., 2.0, and 2.1) of a GNU license.
+*/
+type gnuLicense struct {
+	family  string
+	version string
+}
+
+var gnuLicensePattern = regexp.MustCompile(`(?i)^(A|L)?GPL[-_ ]?v?(\d)(?:\.(\d))?(-only|-or-later|\+)?$`)
+
+func parseGNULicense(name string) (*gnuLicense, bool) {
+	matches := gnuLicensePattern.FindStringSubmatch(name)
+	if matches == nil {
+		return nil, false
+	}
+	return &gnuLicense{family: strings.ToUpper(matches[1]) + "GPL", version: gnuVersion(matches[2], matches[3])}, true
+}
+
+func gnuVersion(major, minor string) string {
+	if minor == "" {
+		minor = "0"
+	}
+	return major + "." + minor
+}
+
+/*
+spdxID returns the SPDX license identifier of the license with the given status, e.g., GPL-2.0-or-later.
+*/
+func (gl *gnuLicense) spdxID(status GrantStatus) string {
+	return gl.family + "-" + gl.version + "-" + string(status)
+}
+
+const (
+	/*maxGrantSources is the maximum number of the source files of each license file for finding the version grant statements.*/
+	maxGrantSources = 200
+	/*maxGrantDocuments is the maximum number of README and NOTICE files of each license file for finding the version grant statements.*/
+	maxGrantDocuments = 20
+	/*maxGrantHeaderSize is the size of the headers of the source files for finding the version grant statements.*/
+	maxGrantHeaderSize = 8 * 1024
+	/*maxGrantFileSize is the maximum size of README and NOTICE files for finding the version grant statements.*/
+	maxGrantFileSize = 256 * 1024
+)
+
+var (
+	commentPattern = regexp.MustCompile(`(?m)^\s*(//+|#+|;+|--|\*+|/\*+|%+|dnl\b|rem\b)`)
+	spaces         = regexp.MustCompile(`\s+`)
+	/* gnuStatementPattern matches the statements like "the GNU General Public License as published by the Free Software Foundation; either version 2". */
+	gnuStatementPattern = regexp.MustCompile(`gnu (?:(lesser|library|affero) )?general public license[^.]{0,100}?version (\d)(?:\.(\d))?`)
+	orLaterPattern      = regexp.MustCompile(`^[^.]{0,80}?(?:any later version|or later)`)
+	/* shortStatementPattern matches the short statements like "GPLv2+", and "LGPL-2.1 or later", and the SPDX identifiers like "GPL-2.0-only". */
+	shortStatementPattern = regexp.MustCompile(`\b(a|l)?gpl[- ]?v?(\d)(?:\.(\d))?(\+|-or-later| or later|-only| only)`)
+)
+
+/*
+grantStatement is a version grant statement found in the project.
+*/
+type grantStatement struct {
+	license *gnuLicense
+	grant   *Grant
+}
+
+/*
+grantScanner finds the version grant statements in the project.
+The statements are scoped to the directory of each license file, and the files in the subdirectories having their own license files
+(e.g., the vendored libraries) belong to those license files, not to the license file of the parent directory.
+The files of a directory are scanned at the first call of grant for the directory, and only if the results contain the GNU licenses.
+ctx is held for the lazy scan in identifying a project, and the scan stops when ctx is done.
+*/
+type grantScanner struct {
+	ctx         context.Context
+	project     Project
+	licenseDirs []string
+	statements  map[string][]*grantStatement
+}
+
+func newGrantScanner(ctx context.Context, project Project) *grantScanner {
+	return &grantScanner{ctx: ctx, project: project, statements: map[string][]*grantStatement{}}
+}
+
+/*
+grant returns the grant of the given license in the license file of the given id.
+If the statements of the license conflict (e.g., both -only and -or-later are found), the grant is undetermined.
+*/
+func (scanner *grantScanner) grant(license *gnuLicense, licenseID string) *Grant {
+	dir := path.Dir(licenseID)
+	statements, ok := scanner.statements[dir]
+	if !ok {
+		statements = scanner.scan(dir)
+		scanner.statements[dir] = statements
+	}
+	var found *Grant
+	for _, statement := range statements {
+		if *statement.license != *license {
+			continue
+		}
+		if found != nil && found.Status != statement.grant.Status {
+			return &Grant{Status: GrantUndetermined}
+		}
+		if found == nil {
+			found = statement.grant
+		}
+	}
+	if found == nil {
+		return &Grant{Status: GrantUndetermined}
+	}
+	return found
+}
+
+/*
+scan finds the statements in the NOTICE, README, and source files belonging to the license files in the given directory.
+The numbers of the scanned files are limited by maxGrantDocuments, and maxGrantSources.
+*/
+func (scanner *grantScanner) scan(dir string) []*grantStatement {
+	statements := []*grantStatement{}
+	documents := 0
+	if finder, ok := scanner.project.(NoticeFinder); ok {
+		for _, id := range scanner.owned(finder.NoticeIDs(), dir) {
+			if documents < maxGrantDocuments {
+				documents++
+				statements = append(statements, scanner.scanFile(id, maxGrantFileSize, finder.NoticeFile)...)
+			}
+		}
+	}
+	finder, ok := scanner.project.(GrantFinder)
+	if !ok {
+		return statements
+	}
+	sources := 0
+	for _, id := range scanner.owned(finder.GrantIDs(), dir) {
+		if isReadmeFile(id) && documents < maxGrantDocuments {
+			documents++
+			statements = append(statements, scanner.scanFile(id, maxGrantFileSize, finder.GrantFile)...)
+		} else if !isReadmeFile(id) && sources < maxGrantSources {
+			sources++
+			statements = append(statements, scanner.scanFile(id, maxGrantHeaderSize, finder.GrantFile)...)
+		}
+	}
+	return statements
+}
+
+/*
+owned returns the ids belonging to the license files in the given directory.
+*/
+func (scanner *grantScanner) owned(ids []string, dir string) []string {
+	results := []string{}
+	for _, id := range ids {
+		if scanner.ownerOf(id) == dir {
+			results = append(results, id)
+		}
+	}
+	return results
+}
+
+/*
+ownerOf returns the deepest directory of the license files containing the given file,
+and the empty string if no license files contain it.
+*/
+func (scanner *grantScanner) ownerOf(id string) string {
+	if scanner.licenseDirs == nil {
+		scanner.licenseDirs = []string{}
+		for _, licenseID := range scanner.project.LicenseIDs() {
+			scanner.licenseDirs = append(scanner.licenseDirs, path.Dir(licenseID))
+		}
+	}
+	owner, depth := "", -1
+	for _, dir := range scanner.licenseDirs {
+		if isInDir(id, dir) && dirDepth(dir) > depth {
+			owner, depth = dir, dirDepth(dir)
+		}
+	}
+	return owner
+}
+
+func isInDir(id, dir string) bool {
+	return dir == "." || strings.HasPrefix(id, dir+"/")
+}
+
+func dirDepth(dir string) int {
+	if dir == "." {
+		return 0
+	}
+	return strings.Count(dir, "/") + 1
+}
+
+func (scanner *grantScanner) scanFile(id string, size int64, opener func(id string) (LicenseFile, error)) []*grantStatement {
+	if scanner.ctx.Err() != nil {
+		return []*grantStatement{}
+	}
+	file, err := opener(id)
+	if err != nil {
+		return []*grantStatement{}
+	}
+	defer file.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(NewContextLicenseFile(scanner.ctx, file), size))
+	if err != nil {
+		return []*grantStatement{}
+	}
+	return findGrantStatements(id, string(data))
+}
+
+/*
+findGrantStatements finds the version grant statements in the given text, ignoring the comment markers, the line breaks, and the cases.
+*/
+func findGrantStatements(source, text string) []*grantStatement {
+	text = strings.ToLower(spaces.ReplaceAllString(commentPattern.ReplaceAllString(text, " "), " "))
+	statements := []*grantStatement{}
+	for _, indexes := range gnuStatementPattern.FindAllStringSubmatchIndex(text, -1) {
+		rest := orLaterPattern.FindString(text[indexes[1]:])
+		status := GrantOnly
+		if rest != "" {
+			status = GrantOrLater
+		}
+		license := &gnuLicense{family: gnuFamily(submatch(text, indexes, 1)), version: gnuVersion(submatch(text, indexes, 2), submatch(text, indexes, 3))}
+		statements = append(statements, newGrantStatement(source, license, status, text[indexes[0]:indexes[1]]+rest))
+	}
+	for _, matches := range shortStatementPattern.FindAllStringSubmatch(text, -1) {
+		license := &gnuLicense{family: strings.ToUpper(matches[1]) + "GPL", version: gnuVersion(matches[2], matches[3])}
+		statements = append(statements, newGrantStatement(source, license, shortStatus(matches[4]), matches[0]))
+	}
+	return statements
+}
+
+func shortStatus(suffix string) GrantStatus {
+	if strings.Contains(suffix, "only") {
+		return GrantOnly
+	}
+	return GrantOrLater
+}
+
+func newGrantStatement(source string, license *gnuLicense, status GrantStatus, text string) *grantStatement {
+	return &grantStatement{license: license, grant: &Grant{Status: status, Source: source, Statement: strings.TrimSpace(text)}}
+}
+
+func gnuFamily(qualifier string) string {
+	switch qualifier {
+	case "lesser", "library":
+		return "LGPL"
+	case "affero":
+		return "AGPL"
+	}
+	return "GPL"
+}
+
+func submatch(text string, indexes []int, group int) string {
+	if indexes[2*group] < 0 {
+		return ""
+	}
+	return text[indexes[2*group]:indexes[2*group+1]]
+}
+
+/*
+refineGrants refines the GNU licenses in the results to the SPDX license identifiers of -only or -or-later variants,
+by the version grant statements for the license file of the given id, and sets the Grant of them.
+The results refined to the same license are merged into the most probable one.
+*/
+func refineGrants(results []*Result, scanner *grantScanner, licenseID string) []*Result {
+	refined := []*Result{}
+	names := map[string]bool{}
+	for _, result := range results {
+		if license, ok := parseGNULicense(result.Name); ok {
+			result.Grant = scanner.grant(license, licenseID)
+			if result.Grant.Status != GrantUndetermined {
+				result.Name = license.spdxID(result.Grant.Status)
+				result.Classification = ClassifyResult(result)
+			}
+		}
+		if !names[result.Expression()] {
+			names[result.Expression()] = true
+			refined = append(refined, result)
+		}
+	}
+	return refined
+}
@@ -0,0 +1,156 @@
+package lioss
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseGNULicense(t *testing.T) {
+	testdata := []struct {
+		give        string
+		wontOk      bool
+		wontFamily  string
+		wontVersion string
+	}{
+		{"GPL-2.0-only", true, "GPL", "2.0"},
+		{"GPL-2.0-or-later", true, "GPL", "2.0"},
+		{"GPL-2.0+", true, "GPL", "2.0"},
+		{"GPLv3.0", true, "GPL", "3.0"},
+		{"LGPLv2.1", true, "LGPL", "2.1"},
+		{"AGPL-3.0", true, "AGPL", "3.0"},
+		{"gpl3", true, "GPL", "3.0"},
+		{"MIT", false, "", ""},
+		{"GPL-2.0-with-classpath-exception", false, "", ""},
+	}
+	for _, td := range testdata {
+		license, ok := parseGNULicense(td.give)
+		if ok != td.wontOk {
+			t.Errorf("parseGNULicense(%s) did not match, wont %v, got %v", td.give, td.wontOk, ok)
+			continue
+		}
+		if ok && (license.family != td.wontFamily || license.version != td.wontVersion) {
+			t.Errorf("parseGNULicense(%s) did not match, wont %s %s, got %s %s", td.give, td.wontFamily, td.wontVersion, license.family, license.version)
+		}
+	}
+}
+
+func TestFindGrantStatements(t *testing.T) {
+	testdata := []struct {
+		give        string
+		wontLicense string
+		wontStatus  GrantStatus
+	}{
+		{`# it under the terms of the GNU General Public License as published by
+# the Free Software Foundation; either version 2 of the License, or
+# (at your option) any later version.`, "GPL-2.0", GrantOrLater},
+		{`/* under the terms of the GNU Lesser General Public License as published by the Free Software Foundation;
+ * either version 2.1 of the License, or (at your option) any later version. */`, "LGPL-2.1", GrantOrLater},
+		{`This program is licensed under the GNU General Public License version 2.`, "GPL-2.0", GrantOnly},
+		{`GNU Affero General Public License, version 3 or later`, "AGPL-3.0", GrantOrLater},
+		{`// SPDX-License-Identifier: GPL-2.0-only`, "GPL-2.0", GrantOnly},
+		{`License: GPLv3+`, "GPL-3.0", GrantOrLater},
+		{`License: LGPL-2.1 only`, "LGPL-2.1", GrantOnly},
+	}
+	for _, td := range testdata {
+		statements := findGrantStatements("README", td.give)
+		if len(statements) != 1 {
+			t.Errorf("%s: size of statements did not match, wont 1, got %d", td.give, len(statements))
+			continue
+		}
+		statement := statements[0]
+		if got := statement.license.family + "-" + statement.license.version; got != td.wontLicense || statement.grant.Status != td.wontStatus {
+			t.Errorf("%s: statement did not match, wont %s %s, got %s %s", td.give, td.wontLicense, td.wontStatus, got, statement.grant.Status)
+		}
+	}
+	if statements := findGrantStatements("README", "MIT License\nCopyright (c) 2021 Foo"); len(statements) != 0 {
+		t.Errorf("statements were found in MIT license: %v", statements)
+	}
+}
+
+func TestRefineGrants(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "lioss")
+	defer os.RemoveAll(dir)
+	license, _ := ioutil.ReadFile("data/misc/GPLv2.0")
+	testdata := []struct {
+		readme      string
+		wontName    string
+		wontStatus  GrantStatus
+		wontSource  string
+		wontResults int
+	}{
+		{"", "GPLv2.0", GrantUndetermined, "", 2},
+		{"Licensed under GPL-2.0-only.", "GPL-2.0-only", GrantOnly, "README", 1},
+		{"Licensed under GPLv2 or later.", "GPL-2.0-or-later", GrantOrLater, "README", 1},
+		{"Licensed under GPLv2 or later, and GPLv2 only.", "GPLv2.0", GrantUndetermined, "", 2},
+		{"Licensed under GPLv3 or later.", "GPLv2.0", GrantUndetermined, "", 2},
+	}
+	for i, td := range testdata {
+		base := filepath.Join(dir, string(rune('a'+i)))
+		os.MkdirAll(base, 0755)
+		ioutil.WriteFile(filepath.Join(base, "LICENSE"), license, 0644)
+		ioutil.WriteFile(filepath.Join(base, "README"), []byte(td.readme), 0644)
+		project, _ := NewProject(base)
+		results := []*Result{{Name: "GPLv2.0", Probability: 0.99}, {Name: "GPL-2.0-or-later", Probability: 0.98}}
+		results = refineGrants(results, newGrantScanner(context.Background(), project), "LICENSE")
+		if len(results) != td.wontResults || results[0].Name != td.wontName || results[0].Grant.Status != td.wontStatus || results[0].Grant.Source != td.wontSource {
+			t.Errorf("%s: refined result did not match, wont %s %s %s (%d), got %s %v (%d)", td.readme, td.wontName, td.wontStatus, td.wontSource, td.wontResults, results[0].Name, results[0].Grant, len(results))
+		}
+	}
+}
+
+func TestIdentifyProjectGrant(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	identifier, _ := NewIdentifier("5gram", 0.75, db)
+	project, _ := NewProject("testdata/project5")
+	defer project.Close()
+	result := identifier.IdentifyProject(project)
+	top := result.Files[0].Results[0]
+	if top.Name != "GPL-2.0-or-later" || top.Grant == nil || top.Grant.Source != "README.md" {
+		t.Errorf("the top result of project5 did not match, wont GPL-2.0-or-later granted by README.md, got %s %v", top.Name, top.Grant)
+	}
+}
+
+func TestGrantsScopedToLicenseDirectories(t *testing.T) {
+	license, _ := ioutil.ReadFile("data/misc/GPLv2.0")
+	fsys := fstest.MapFS{
+		"LICENSE":                 {Data: license},
+		"README.md":               {Data: []byte("Licensed under GPL-2.0-only.")},
+		"src/main.c":              {Data: []byte("/* SPDX-License-Identifier: GPL-2.0-only */")},
+		"vendor/lib/LICENSE":      {Data: license},
+		"vendor/lib/README":       {Data: []byte("Licensed under GPLv2 or later.")},
+		"vendor/lib/src/lib.c":    {Data: []byte("/* SPDX-License-Identifier: GPL-2.0-or-later */")},
+		"vendor/nolicense/util.c": {Data: []byte("/* SPDX-License-Identifier: GPL-2.0-only */")},
+	}
+	scanner := newGrantScanner(context.Background(), NewFSProject("memory", fsys))
+	testdata := []struct {
+		licenseID  string
+		wontStatus GrantStatus
+	}{
+		{"LICENSE", GrantOnly},
+		{"vendor/lib/LICENSE", GrantOrLater},
+	}
+	for _, td := range testdata {
+		results := refineGrants([]*Result{{Name: "GPLv2.0", Probability: 0.99}}, scanner, td.licenseID)
+		if results[0].Grant.Status != td.wontStatus {
+			t.Errorf("%s: grant did not match, wont %s, got %s (%s)", td.licenseID, td.wontStatus, results[0].Grant.Status, results[0].Grant.Source)
+		}
+	}
+}
+
+func TestGrantDocumentsLimited(t *testing.T) {
+	license, _ := ioutil.ReadFile("data/misc/GPLv2.0")
+	fsys := fstest.MapFS{"LICENSE": {Data: license}}
+	for i := 0; i < maxGrantDocuments; i++ {
+		fsys[fmt.Sprintf("docs/%02d/README", i)] = &fstest.MapFile{Data: []byte("See the LICENSE file.")}
+	}
+	fsys["docs/zz/more/README"] = &fstest.MapFile{Data: []byte("Licensed under GPLv2 or later.")}
+	scanner := newGrantScanner(context.Background(), NewFSProject("memory", fsys))
+	if grant := scanner.grant(&gnuLicense{family: "GPL", version: "2.0"}, "LICENSE"); grant.Status != GrantUndetermined {
+		t.Errorf("README files over the limit should not be scanned, got %s (%s)", grant.Status, grant.Source)
+	}
+}
@@ -1,17 +1,39 @@
 package lioss
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"sort"
+	"strings"
+
+	"github.com/tamada/lioss/lib"
 )
 
 /*
 Identifier is a type for identifying the license.
+Identifier is safe for concurrent use by multiple goroutines, as long as its fields are not modified.
+
+If the database has MinHash signatures, and the comparator is indexable (see IndexableAlgorithm),
+the identifier shortlists the candidate licenses by the LSH index, and compares the given license with only the candidates.
+Exhaustive disables the index, and compares the given license with all of licenses in the database.
+
+TopN limits the number of results for each license file (not positive means no limits),
+and MinMargin flags the license file as ambiguous if the margin of probabilities between the first and the second candidates is less than it (see Ambiguity).
+Observer is notified the events in identifying the projects, if it is not nil.
 */
 type Identifier struct {
-	Threshold  float64
-	Comparator Algorithm
-	Database   *Database
+	Threshold          float64
+	ExceptionThreshold float64
+	Exhaustive         bool
+	TopN               int
+	MinMargin          float64
+	Observer           Observer
+	Comparator         Algorithm
+	Database           *Database
+	index              *lshIndex
+	normalizer         *lib.Normalizer
 }
 
 /*
@@ -19,69 +41,181 @@ Result shows identified license and its probability.
 */
 type Result struct {
 	/*Name shows the license name.*/
-	Name string
+	Name string `json:"name"`
 	/*Probability represents the probability of the license, by range of 0.0 to 1.0.*/
-	Probability float64
+	Probability float64 `json:"probability"`
+	/*Exception shows the license exception found with the license, and it is empty if no exceptions are found.*/
+	Exception string `json:"exception,omitempty"`
+	/*ExceptionProbability represents the probability of the license exception, by range of 0.0 to 1.0.*/
+	ExceptionProbability float64 `json:"exception-probability,omitempty"`
+	/*Breakdown shows the probabilities by each algorithm, if the comparator is the ensemble algorithm.*/
+	Breakdown map[string]float64 `json:"breakdown,omitempty"`
+	/*Grant shows the version grant of the GNU licenses (e.g., -only, or -or-later) found in the project, and it is nil for the other licenses.*/
+	Grant *Grant `json:"grant,omitempty"`
+	/*Classification shows the category, obligations, and limitations of the license, and it is nil if the license is not in the knowledge base.*/
+	Classification *Classification `json:"classification,omitempty"`
+}
+
+/*
+Expression returns the license name in the form of SPDX license expression, e.g., "GPL-2.0-only WITH Classpath-exception-2.0".
+*/
+func (result *Result) Expression() string {
+	if result.Exception == "" {
+		return result.Name
+	}
+	return fmt.Sprintf("%s WITH %s", result.Name, result.Exception)
 }
 
 func (result *Result) String() string {
-	return fmt.Sprintf("%s (%f)", result.Name, result.Probability)
+	return fmt.Sprintf("%s (%f)", result.Expression(), result.Probability)
 }
 
 /*
 NewIdentifier creates an instance of Identifier with the given arguments.
 The range of threshold must be from 0.0 to 1.0.
+This function returns an error if the database has no licenses for the given algorithm (e.g., "5shingle" is not built into the database).
 */
 func NewIdentifier(algorithmName string, threshold float64, db *Database) (*Identifier, error) {
 	identifier := new(Identifier)
 	identifier.Threshold = threshold
+	identifier.ExceptionThreshold = DefaultExceptionThreshold
 	algorithm, err := NewAlgorithm(algorithmName)
 	if err != nil {
 		return nil, err
 	}
 	identifier.Comparator = algorithm
 	identifier.Database = db
+	if identifier.normalizer, err = db.Normalizer(); err != nil {
+		return nil, err
+	}
 	algorithm.Prepare(db)
+	if len(identifier.Entries()) == 0 {
+		return nil, fmt.Errorf("%s: no licenses for the algorithm in the database", algorithm.String())
+	}
+	if db.MinHash != nil && isIndexable(algorithm) {
+		identifier.index = buildIndex(db.MinHash, identifier.Entries())
+	}
 	return identifier, nil
 }
 
+/*
+Normalizer returns the normalizer of the identifier, which is the same as the normalizer of the database.
+*/
+func (identifier *Identifier) Normalizer() *lib.Normalizer {
+	if identifier.normalizer == nil {
+		normalizer, _ := lib.NewNormalizer()
+		return normalizer
+	}
+	return identifier.normalizer
+}
+
+/*
+Entries returns the licenses in the database for the comparator of the identifier.
+*/
+func (identifier *Identifier) Entries() []*License {
+	if provider, ok := identifier.Comparator.(EntriesProvider); ok {
+		return provider.Entries(identifier.Database)
+	}
+	return identifier.Database.Entries(identifier.Comparator.String())
+}
+
+/*
+ExceptionEntries returns the license exceptions in the database for the comparator of the identifier.
+*/
+func (identifier *Identifier) ExceptionEntries() []*License {
+	if provider, ok := identifier.Comparator.(EntriesProvider); ok {
+		return provider.ExceptionEntries(identifier.Database)
+	}
+	return identifier.Database.ExceptionEntries(identifier.Comparator.String())
+}
+
 /*Identify identifies the license of the given project. */
 func (identifier *Identifier) Identify(project Project) (map[LicenseFile][]*Result, error) {
-	ids := project.LicenseIDs()
-	resultMap := map[LicenseFile][]*Result{}
-	for _, id := range ids {
-		file, results, err := identifier.identifyEach(project, id)
-		if err != nil {
-			return resultMap, err
-		}
-		resultMap[file] = results
+	return identifier.IdentifyContext(context.Background(), project)
+}
+
+/*
+IdentifyContext identifies the license of the given project, and stops identifying when ctx is done.
+*/
+func (identifier *Identifier) IdentifyContext(ctx context.Context, project Project) (map[LicenseFile][]*Result, error) {
+	result := identifier.IdentifyProjectContext(ctx, project)
+	return result.ResultMap(), result.Err
+}
+
+/*
+IdentifyReader identifies the license of the text read from the given reader, without the filesystem.
+See IdentifyText for the details.
+*/
+func (identifier *Identifier) IdentifyReader(reader io.Reader) (*FileResult, error) {
+	return identifier.IdentifyReaderContext(context.Background(), reader)
+}
+
+/*
+IdentifyReaderContext identifies the license of the text read from the given reader, and stops identifying when ctx is done.
+*/
+func (identifier *Identifier) IdentifyReaderContext(ctx context.Context, reader io.Reader) (*FileResult, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+	return identifier.IdentifyTextContext(ctx, string(data))
+}
+
+/*
+IdentifyText identifies the license of the given text, without the filesystem.
+The id of the license file of the result is TextID, and the version grants of the GNU licenses are undetermined,
+since the notices of the project are not available.
+*/
+func (identifier *Identifier) IdentifyText(text string) (*FileResult, error) {
+	return identifier.IdentifyTextContext(context.Background(), text)
+}
+
+/*
+IdentifyTextContext identifies the license of the given text, and stops identifying when ctx is done.
+*/
+func (identifier *Identifier) IdentifyTextContext(ctx context.Context, text string) (*FileResult, error) {
+	result := identifier.IdentifyProjectContext(ctx, NewTextProject("", text))
+	if result.Err != nil {
+		return nil, result.Err
 	}
-	return resultMap, nil
+	return result.Files[0], nil
 }
 
-func (identifier *Identifier) identifyEach(project Project, id string) (LicenseFile, []*Result, error) {
+func (identifier *Identifier) identifyEach(ctx context.Context, project Project, id string, scanner *grantScanner) (*FileResult, error) {
 	file, err := project.LicenseFile(id)
 	if err != nil {
-		return file, nil, err
+		return &FileResult{File: file}, contextError(ctx, err)
+	}
+	license, text, err := identifier.readLicense(NewContextLicenseFile(ctx, file))
+	if err != nil {
+		return &FileResult{File: file}, contextError(ctx, err)
 	}
-	license, err := identifier.readLicense(file)
+	results, err := identifier.identify(ctx, license)
 	if err != nil {
-		return file, nil, err
+		return &FileResult{File: file}, err
+	}
+	results = refineGrants(results, scanner, id)
+	if err := ctx.Err(); err != nil {
+		return &FileResult{File: file}, err
 	}
-	results, err := identifier.identify(license)
-	return file, results, err
+	return &FileResult{File: file, Results: identifier.top(results), Copyrights: lib.ExtractCopyrights(text), Ambiguity: identifier.detectAmbiguity(results, text)}, nil
 }
 
 /*
-ReadLicense reads License from given LicenseFile.
+readLicense reads License from given LicenseFile, and also returns the text of the file.
+The copyright notices in the file are stripped by the copyright step of the normalizer, as the licenses in the database.
 */
-func (identifier *Identifier) readLicense(file LicenseFile) (*License, error) {
-	license, err := identifier.Comparator.Parse(file, file.ID())
+func (identifier *Identifier) readLicense(file LicenseFile) (*License, string, error) {
 	defer file.Close()
+	data, err := ioutil.ReadAll(file)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %s", file.ID(), err.Error())
+		return nil, "", fmt.Errorf("%s: %s", file.ID(), err.Error())
 	}
-	return license, nil
+	license, err := ParseLicense(identifier.Normalizer(), identifier.Comparator, strings.NewReader(string(data)), file.ID())
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %s", file.ID(), err.Error())
+	}
+	return license, string(data), nil
 }
 
 func filter(results []*Result, threshold float64) []*Result {
@@ -97,12 +231,38 @@ func filter(results []*Result, threshold float64) []*Result {
 	return filteredResults
 }
 
-func (identifier *Identifier) identify(baseLicense *License) ([]*Result, error) {
-	licenses := identifier.Database.Entries(identifier.Comparator.String())
+/*
+candidates returns the licenses compared with the given license.
+The MinHash index shortlists them only for the indexable comparators (see IndexableAlgorithm),
+and all of licenses are returned if the index finds no candidates.
+*/
+func (identifier *Identifier) candidates(license *License) []*License {
+	if identifier.Exhaustive || identifier.index == nil {
+		return identifier.Entries()
+	}
+	if candidates := identifier.index.candidates(license); len(candidates) > 0 {
+		return candidates
+	}
+	return identifier.Entries()
+}
+
+func (identifier *Identifier) compare(baseLicense, license *License) *Result {
+	if comparator, ok := identifier.Comparator.(DetailedComparator); ok {
+		similarity, breakdown := comparator.CompareDetails(baseLicense, license)
+		return &Result{Name: license.Name, Probability: similarity, Breakdown: breakdown}
+	}
+	return &Result{Name: license.Name, Probability: identifier.Comparator.Compare(baseLicense, license)}
+}
+
+func (identifier *Identifier) identify(ctx context.Context, baseLicense *License) ([]*Result, error) {
+	licenses := identifier.candidates(baseLicense)
 	results := []*Result{}
 	for _, license := range licenses {
-		similarity := identifier.Comparator.Compare(baseLicense, license)
-		results = append(results, &Result{Name: license.Name, Probability: similarity})
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		results = append(results, identifier.compare(baseLicense, license))
 	}
-	return filter(results, identifier.Threshold), nil
+	results = filter(results, identifier.Threshold)
+	return classifyResults(attachException(results, identifier.identifyException(baseLicense))), nil
 }
@@ -1,6 +1,7 @@
 package lioss
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -69,8 +70,8 @@ func TestIdentifier(t *testing.T) {
 	db, _ := ReadDatabase("testdata/test.liossdb")
 	for _, td := range testdata {
 		identifier, _ := NewIdentifier(td.algorithm, td.threshold, db)
-		license, _ := identifier.readLicense(createLicenseFile(td.givePath))
-		results, err := identifier.identify(license)
+		license, _, _ := identifier.readLicense(createLicenseFile(td.givePath))
+		results, err := identifier.identify(context.Background(), license)
 		if (err == nil) != td.successFlag {
 			t.Errorf("the result of identify (%s, %s) did not match, wont %v", td.algorithm, td.givePath, td.successFlag)
 		}
@@ -85,3 +86,54 @@ func createLicenseFile(path string) LicenseFile {
 	file, _ := os.Open(path)
 	return &basicLicenseFile{id: name, reader: file}
 }
+
+func TestIdentifyText(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	identifier, _ := NewIdentifier("5gram", 0.75, db)
+	testdata := []struct {
+		givePath string
+		wontName string
+	}{
+		{"data/misc/MIT", "MIT"},
+		{"testdata/project2/license.txt", "GPLv3.0"},
+	}
+	for _, td := range testdata {
+		reader, _ := os.Open(td.givePath)
+		defer reader.Close()
+		result, err := identifier.IdentifyReader(reader)
+		if err != nil {
+			t.Errorf("IdentifyReader(%s) failed: %s", td.givePath, err.Error())
+			continue
+		}
+		if result.File.ID() != TextID || len(result.Results) == 0 || result.Results[0].Name != td.wontName {
+			t.Errorf("IdentifyReader(%s) did not match, wont %s, got %v", td.givePath, td.wontName, result.Results)
+		}
+		if grant := result.Results[0].Grant; grant != nil && grant.Status != GrantUndetermined {
+			t.Errorf("IdentifyReader(%s): version grant should be undetermined, got %s", td.givePath, grant.Status)
+		}
+	}
+	result, err := identifier.IdentifyText("")
+	if err != nil || len(result.Results) != 0 {
+		t.Errorf("IdentifyText(\"\") did not match, wont no results, got %v (%v)", result, err)
+	}
+}
+
+func TestTextProject(t *testing.T) {
+	project := NewTextProject("-", "some text")
+	defer project.Close()
+	if project.BasePath() != "-" || len(project.LicenseIDs()) != 1 || project.LicenseIDs()[0] != TextID {
+		t.Errorf("text project did not match, got %s %v", project.BasePath(), project.LicenseIDs())
+	}
+	if _, err := project.LicenseFile("NOTICE"); err == nil {
+		t.Errorf("text project has NOTICE file")
+	}
+}
+
+func TestNewIdentifierWithoutEntries(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	for _, algorithm := range []string{"5shingle", "7shingle-containment", "ensemble:5gram,5shingle"} {
+		if _, err := NewIdentifier(algorithm, 0.75, db); err == nil {
+			t.Errorf("NewIdentifier(%s) should be failed, since the database has no licenses for it", algorithm)
+		}
+	}
+}
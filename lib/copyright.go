@@ -0,0 +1,99 @@
+package lib
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+)
+
+/*
+Copyright shows a copyright statement, its years, and its holder.
+*/
+type Copyright struct {
+	Statement string   `json:"statement"`
+	Years     []string `json:"years,omitempty"`
+	Holder    string   `json:"holder"`
+}
+
+var commentPrefixPattern = regexp.MustCompile(`^[ \t]*(?:/\*+|\*+|//+|#+|;+|--|%+|rem\b)?[ \t]*`)
+var copyrightPrefixPattern = regexp.MustCompile(`^(?:Portions\s+)?(?:Copyright\b|COPYRIGHT\b|\([cC]\)|©)`)
+var copyrightLeadingPattern = regexp.MustCompile(`^(?i:portions\s+)?(?i:copyright\b|\(c\)|©)[\s,;:-]*`)
+var copyrightSymbolPattern = regexp.MustCompile(`(?i)copyright\b|\(c\)|©`)
+var copyrightYearPattern = regexp.MustCompile(`\b(?:19|20)[0-9]{2}(?:\s*[-–]\s*(?:(?:19|20)[0-9]{2}|[pP]resent))?\b`)
+var copyrightPlaceholderPattern = regexp.MustCompile(`(?i)<[^>]*>|\[[^\]]*\]`)
+var allRightsReservedPattern = regexp.MustCompile(`(?i)all\s+rights\s+reserved\.?`)
+var spacesPattern = regexp.MustCompile(`\s+`)
+
+/*
+isCopyrightLine returns true if the given line (without comment prefixes) is a copyright notice.
+The line must begin with "Copyright", "(c)", or "©", and contain a year, the copyright symbol, or the placeholder (e.g., "<year>"),
+and the line beginning with "(c)" must contain a year,
+therefore, the sentences of license terms beginning with "copyright" are not treated as notices.
+*/
+func isCopyrightLine(line string) bool {
+	if !copyrightPrefixPattern.MatchString(line) {
+		return false
+	}
+	rest := copyrightPrefixPattern.ReplaceAllString(line, "")
+	if strings.HasPrefix(line, "(") {
+		/* "(c)" is also used for the numbering of lists, therefore, the year is required. */
+		return copyrightYearPattern.MatchString(rest)
+	}
+	return copyrightYearPattern.MatchString(rest) || copyrightSymbolPattern.MatchString(rest) ||
+		copyrightPlaceholderPattern.MatchString(rest) || strings.HasPrefix(line, "©")
+}
+
+func stripCommentPrefix(line string) string {
+	return strings.TrimSpace(commentPrefixPattern.ReplaceAllString(line, ""))
+}
+
+/*
+ExtractCopyrights extracts the copyright statements from the given text, such as LICENSE files, and source file headers.
+The comment prefixes of the lines (e.g., "//", "#", and " * ") are ignored.
+The statements with placeholders only (e.g., "Copyright <year> <copyright holders>") are not returned.
+*/
+func ExtractCopyrights(text string) []*Copyright {
+	results := []*Copyright{}
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := stripCommentPrefix(scanner.Text())
+		if !isCopyrightLine(line) {
+			continue
+		}
+		if copyright := parseCopyright(line); copyright.Holder != "" {
+			results = append(results, copyright)
+		}
+	}
+	return results
+}
+
+func parseCopyright(line string) *Copyright {
+	statement := spacesPattern.ReplaceAllString(line, " ")
+	copyright := &Copyright{Statement: statement, Years: copyrightYearPattern.FindAllString(statement, -1)}
+	holder := allRightsReservedPattern.ReplaceAllString(statement, "")
+	holder = copyrightYearPattern.ReplaceAllString(holder, "")
+	for copyrightLeadingPattern.MatchString(holder) {
+		holder = copyrightLeadingPattern.ReplaceAllString(holder, "")
+	}
+	if strings.Trim(copyrightPlaceholderPattern.ReplaceAllString(holder, ""), " ,;:-.") == "" {
+		return copyright
+	}
+	holder = strings.TrimPrefix(strings.TrimSpace(holder), "by ")
+	copyright.Holder = strings.Trim(spacesPattern.ReplaceAllString(holder, " "), " ,;:-.")
+	return copyright
+}
+
+/*
+StripCopyrights removes the lines of copyright notices from the given text.
+*/
+func StripCopyrights(text string) string {
+	lines := strings.Split(text, "\n")
+	results := []string{}
+	for _, line := range lines {
+		if !isCopyrightLine(stripCommentPrefix(line)) {
+			results = append(results, line)
+		}
+	}
+	return strings.Join(results, "\n")
+}
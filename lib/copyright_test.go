@@ -0,0 +1,55 @@
+package lib
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractCopyrights(t *testing.T) {
+	testdata := []struct {
+		giveText    string
+		wontHolders []string
+		wontYears   [][]string
+	}{
+		{"Copyright (c) 2019 Some Person", []string{"Some Person"}, [][]string{{"2019"}}},
+		{"Copyright (C) 2007 Free Software Foundation, Inc. <https://fsf.org/>", []string{"Free Software Foundation, Inc. <https://fsf.org/>"}, [][]string{{"2007"}}},
+		{" * Copyright 2015-2020, 2021 Foo Bar. All rights reserved.", []string{"Foo Bar"}, [][]string{{"2015-2020", "2021"}}},
+		{"// (c) 2020 by ACME", []string{"ACME"}, [][]string{{"2020"}}},
+		{"# Copyright © 1999 Hoge\n# Copyright 2000 Fuga", []string{"Hoge", "Fuga"}, [][]string{{"1999"}, {"2000"}}},
+		{"Copyright <YEAR> <COPYRIGHT HOLDER>", []string{}, [][]string{}},
+		{"Copyright [yyyy] [name of copyright owner]", []string{}, [][]string{}},
+		{"copyright notice and this permission notice", []string{}, [][]string{}},
+		{"Copyright and related rights are waived.", []string{}, [][]string{}},
+	}
+	for _, td := range testdata {
+		copyrights := ExtractCopyrights(td.giveText)
+		holders := []string{}
+		years := [][]string{}
+		for _, copyright := range copyrights {
+			holders = append(holders, copyright.Holder)
+			years = append(years, copyright.Years)
+		}
+		if !reflect.DeepEqual(holders, td.wontHolders) {
+			t.Errorf("holders of %s did not match, wont %v, got %v", td.giveText, td.wontHolders, holders)
+		}
+		if !reflect.DeepEqual(years, td.wontYears) {
+			t.Errorf("years of %s did not match, wont %v, got %v", td.giveText, td.wontYears, years)
+		}
+	}
+}
+
+func TestStripCopyrights(t *testing.T) {
+	testdata := []struct {
+		giveText string
+		wont     string
+	}{
+		{"Copyright (c) 2020 Foo\nThe above copyright notice\ncopyright holders", "The above copyright notice\ncopyright holders"},
+		{"MIT License\n\n(C) 2021 Bar\n© Baz\nPermission", "MIT License\n\nPermission"},
+		{"(b) item\n(c) You must retain", "(b) item\n(c) You must retain"},
+	}
+	for _, td := range testdata {
+		if got := StripCopyrights(td.giveText); got != td.wont {
+			t.Errorf("StripCopyrights(%q) did not match, wont %q, got %q", td.giveText, td.wont, got)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package lib
+
+import "strings"
+
+/*
+DiffKind shows the kind of differences, that is, DiffEqual, DiffInsert, or DiffDelete.
+*/
+type DiffKind int
+
+const (
+	/*DiffEqual shows the words appear in both texts.*/
+	DiffEqual DiffKind = iota
+	/*DiffInsert shows the words appear only in the target text.*/
+	DiffInsert
+	/*DiffDelete shows the words appear only in the original text.*/
+	DiffDelete
+)
+
+/*
+DiffOp is a run of words with the same kind in the differences.
+*/
+type DiffOp struct {
+	Kind DiffKind
+	Text string
+}
+
+/*
+maxDiffEdits is the limit of the edit distance for computing the differences.
+If the edit distance exceeds the limit, the texts are treated as entirely different, for bounding the time and memory.
+*/
+const maxDiffEdits = 4000
+
+/*
+WordDiff computes the differences of words between the original text (e.g., the canonical license text) and the target text.
+The words are compared ignoring the cases and the punctuations around them, and the equal words are shown by those of the target text.
+*/
+func WordDiff(original, target string) []*DiffOp {
+	words1 := strings.Fields(original)
+	words2 := strings.Fields(target)
+	keys1 := diffKeys(words1)
+	keys2 := diffKeys(words2)
+	prefix := commonPrefix(keys1, keys2)
+	suffix := commonPrefix(reverseStrings(keys1[prefix:]), reverseStrings(keys2[prefix:]))
+	builder := &diffBuilder{ops: []*DiffOp{}}
+	builder.append(DiffEqual, words2[:prefix]...)
+	middle1 := keys1[prefix : len(keys1)-suffix]
+	middle2 := keys2[prefix : len(keys2)-suffix]
+	kinds, ok := myers(middle1, middle2)
+	if !ok {
+		builder.append(DiffDelete, words1[prefix:len(words1)-suffix]...)
+		builder.append(DiffInsert, words2[prefix:len(words2)-suffix]...)
+	}
+	x, y := prefix, prefix
+	for _, kind := range kinds {
+		switch kind {
+		case DiffEqual:
+			builder.append(kind, words2[y])
+			x, y = x+1, y+1
+		case DiffDelete:
+			builder.append(kind, words1[x])
+			x++
+		case DiffInsert:
+			builder.append(kind, words2[y])
+			y++
+		}
+	}
+	builder.append(DiffEqual, words2[len(words2)-suffix:]...)
+	return builder.ops
+}
+
+type diffBuilder struct {
+	ops []*DiffOp
+}
+
+func (builder *diffBuilder) append(kind DiffKind, words ...string) {
+	if len(words) == 0 {
+		return
+	}
+	text := strings.Join(words, " ")
+	if length := len(builder.ops); length > 0 && builder.ops[length-1].Kind == kind {
+		builder.ops[length-1].Text = builder.ops[length-1].Text + " " + text
+		return
+	}
+	builder.ops = append(builder.ops, &DiffOp{Kind: kind, Text: text})
+}
+
+func diffKeys(words []string) []string {
+	keys := make([]string, len(words))
+	for i, word := range words {
+		keys[i] = strings.ToLower(strings.Trim(word, `.,;:!?"'()[]<>`))
+	}
+	return keys
+}
+
+func commonPrefix(keys1, keys2 []string) int {
+	i := 0
+	for i < len(keys1) && i < len(keys2) && keys1[i] == keys2[i] {
+		i++
+	}
+	return i
+}
+
+func reverseStrings(slice []string) []string {
+	results := make([]string, len(slice))
+	for i, item := range slice {
+		results[len(slice)-i-1] = item
+	}
+	return results
+}
+
+/*
+myers computes the shortest edit script from a to b by the Myers' algorithm, and returns the kinds of edits in order.
+This function returns false if the edit distance exceeds maxDiffEdits.
+*/
+func myers(a, b []string) ([]DiffKind, bool) {
+	n, m := len(a), len(b)
+	trace := [][]int{}
+	v := map[int]int{1: 0}
+	for d := 0; d <= n+m; d++ {
+		if d > maxDiffEdits {
+			return []DiffKind{}, false
+		}
+		snapshot := make([]int, 2*d+3)
+		for k := -d - 1; k <= d+1; k++ {
+			snapshot[k+d+1] = v[k]
+		}
+		trace = append(trace, snapshot)
+		for k := -d; k <= d; k += 2 {
+			x := v[k-1] + 1
+			if k == -d || (k != d && v[k-1] < v[k+1]) {
+				x = v[k+1]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, n, m), true
+			}
+		}
+	}
+	return backtrack(trace, n, m), true
+}
+
+func backtrack(trace [][]int, n, m int) []DiffKind {
+	kinds := []DiffKind{}
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := func(k int) int { return trace[d][k+d+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v(k-1) < v(k+1)) {
+			prevK = k + 1
+		}
+		prevX := v(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			kinds = append(kinds, DiffEqual)
+			x, y = x-1, y-1
+		}
+		if d > 0 && x == prevX {
+			kinds = append(kinds, DiffInsert)
+		} else if d > 0 {
+			kinds = append(kinds, DiffDelete)
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(kinds)-1; i < j; i, j = i+1, j-1 {
+		kinds[i], kinds[j] = kinds[j], kinds[i]
+	}
+	return kinds
+}
@@ -0,0 +1,45 @@
+package lib
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func diffString(ops []*DiffOp) string {
+	results := []string{}
+	for _, op := range ops {
+		switch op.Kind {
+		case DiffInsert:
+			results = append(results, fmt.Sprintf("+[%s]", op.Text))
+		case DiffDelete:
+			results = append(results, fmt.Sprintf("-[%s]", op.Text))
+		default:
+			results = append(results, op.Text)
+		}
+	}
+	return strings.Join(results, " ")
+}
+
+func TestWordDiff(t *testing.T) {
+	testdata := []struct {
+		original string
+		target   string
+		wont     string
+	}{
+		{"a b c", "a b c", "a b c"},
+		{"a b c", "a x c", "a -[b] +[x] c"},
+		{"a b c", "a b c d", "a b c +[d]"},
+		{"a b c", "b c", "-[a] b c"},
+		{"The Software is provided", "the software, is PROVIDED", "the software, is PROVIDED"},
+		{"a b", "", "-[a b]"},
+		{"", "a b", "+[a b]"},
+		{"", "", ""},
+	}
+	for _, td := range testdata {
+		got := diffString(WordDiff(td.original, td.target))
+		if got != td.wont {
+			t.Errorf("WordDiff(%s, %s) did not match, wont %s, got %s", td.original, td.target, td.wont, got)
+		}
+	}
+}
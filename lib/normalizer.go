@@ -0,0 +1,188 @@
+package lib
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+/*
+NormalizerVersion shows the version of the normalization pipeline.
+The version is increased when the behavior of the steps changes, and the databases built by the other version are detected as mismatch.
+Version 2 strips the copyright notices by StripCopyrights in the copyright step, which keeps the list items starting with "(c)".
+Version 3 replaces "wilful" to "willful" in the equivalent-words step.
+*/
+const NormalizerVersion = "3"
+
+/*
+NormalizeStep shows a step of the normalization pipeline.
+*/
+type NormalizeStep struct {
+	Name        string
+	Description string
+	apply       func(text string) string
+}
+
+/*
+Normalizer normalizes the license texts by the pipeline of steps, based on the SPDX matching guidelines.
+The steps are applied in the order of NormalizeSteps, and the whitespaces are always collapsed by Normalize at last.
+The zero value of Normalizer (no steps), and nil are the same as Normalize.
+*/
+type Normalizer struct {
+	steps []*NormalizeStep
+}
+
+var listNumberingPattern = regexp.MustCompile(`(?m)^[ \t]*(?:[-*•·]|\(?(?:[0-9]+|[a-zA-Z]|[ivxlcdm]+)[.)](?:[0-9]+[.)]?)*)[ \t]+`)
+var httpPattern = regexp.MustCompile(`(?i)https://`)
+var dashReplacer = strings.NewReplacer("‐", "-", "‑", "-", "‒", "-", "–", "-", "—", "-", "―", "-", "−", "-")
+var quoteReplacer = strings.NewReplacer("“", "\"", "”", "\"", "„", "\"", "«", "\"", "»", "\"", "''", "\"", "``", "\"", "‘", "'", "’", "'", "‚", "'", "`", "'")
+
+/*
+equivalentWords is the list of equivalent words in the SPDX matching guidelines (a part of them).
+The keys are replaced to the values.
+*/
+var equivalentWords = [][2]string{
+	{"acknowledgment", "acknowledgement"}, {"analogue", "analog"}, {"analyse", "analyze"}, {"artefact", "artifact"},
+	{"authorisation", "authorization"}, {"authorised", "authorized"}, {"calibre", "caliber"}, {"cancelled", "canceled"},
+	{"capitalisations", "capitalizations"}, {"catalogue", "catalog"}, {"categorise", "categorize"}, {"centre", "center"},
+	{"copyright holder", "copyright owner"}, {"emphasised", "emphasized"}, {"favour", "favor"}, {"favourite", "favorite"},
+	{"fulfilment", "fulfillment"}, {"fulfil", "fulfill"}, {"initialise", "initialize"}, {"judgment", "judgement"},
+	{"labelling", "labeling"}, {"labour", "labor"}, {"licence", "license"}, {"maximise", "maximize"},
+	{"modelled", "modeled"}, {"modelling", "modeling"}, {"noncommercial", "non-commercial"}, {"offence", "offense"},
+	{"optimise", "optimize"}, {"organisation", "organization"}, {"organise", "organize"}, {"per cent", "percent"},
+	{"practise", "practice"}, {"programme", "program"}, {"realise", "realize"}, {"recognise", "recognize"},
+	{"signalling", "signaling"}, {"sub-license", "sublicense"}, {"sub license", "sublicense"}, {"utilisation", "utilization"},
+	{"whilst", "while"}, {"wilful", "willful"},
+}
+
+var equivalentWordsPattern = buildEquivalentWordsPattern()
+
+func buildEquivalentWordsPattern() *regexp.Regexp {
+	words := []string{}
+	for _, pair := range equivalentWords {
+		words = append(words, regexp.QuoteMeta(pair[0]))
+	}
+	return regexp.MustCompile(`\b(?:` + strings.Join(words, "|") + `)\b`)
+}
+
+func replaceEquivalentWords(text string) string {
+	return equivalentWordsPattern.ReplaceAllStringFunc(text, func(word string) string {
+		for _, pair := range equivalentWords {
+			if pair[0] == word {
+				return pair[1]
+			}
+		}
+		return word
+	})
+}
+
+/*
+NormalizeSteps returns the available steps of the normalization pipeline in the applied order.
+*/
+func NormalizeSteps() []*NormalizeStep {
+	return []*NormalizeStep{
+		{Name: "copyright", Description: "ignores the copyright notices.", apply: func(text string) string {
+			return StripCopyrights(text)
+		}},
+		{Name: "list-numbering", Description: "ignores the bullets and the numbering of lists.", apply: func(text string) string {
+			return listNumberingPattern.ReplaceAllString(text, "")
+		}},
+		{Name: "lowercase", Description: "ignores the cases.", apply: strings.ToLower},
+		{Name: "punctuation", Description: "treats the variants of quotes and dashes as the same.", apply: func(text string) string {
+			return quoteReplacer.Replace(dashReplacer.Replace(text))
+		}},
+		{Name: "http", Description: "treats \"https://\" as \"http://\".", apply: func(text string) string {
+			return httpPattern.ReplaceAllString(text, "http://")
+		}},
+		{Name: "equivalent-words", Description: "treats the equivalent words (e.g., licence and license) as the same.", apply: replaceEquivalentWords},
+	}
+}
+
+/*
+NormalizeStepNames returns the names of the available steps in the applied order.
+*/
+func NormalizeStepNames() []string {
+	names := []string{}
+	for _, step := range NormalizeSteps() {
+		names = append(names, step.Name)
+	}
+	return names
+}
+
+/*
+NewNormalizer creates the normalizer with the given step names.
+The steps are applied in the order of NormalizeSteps, regardless of the order of the given names.
+"all" means all of the steps, and "none" means no steps.
+*/
+func NewNormalizer(names ...string) (*Normalizer, error) {
+	given := map[string]bool{}
+	for _, name := range names {
+		given[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	normalizer := &Normalizer{steps: []*NormalizeStep{}}
+	for _, step := range NormalizeSteps() {
+		if given[step.Name] || given["all"] {
+			normalizer.steps = append(normalizer.steps, step)
+			delete(given, step.Name)
+		}
+	}
+	delete(given, "all")
+	delete(given, "none")
+	delete(given, "")
+	for name := range given {
+		return nil, fmt.Errorf("%s: unknown normalization step", name)
+	}
+	return normalizer, nil
+}
+
+/*
+DefaultNormalizer returns the normalizer with all of the steps.
+*/
+func DefaultNormalizer() *Normalizer {
+	normalizer, _ := NewNormalizer("all")
+	return normalizer
+}
+
+/*
+ParseNormalizerVersion creates the normalizer from the version string (the result of Version).
+The empty string means the normalizer without steps, which is the behavior before the pipeline introduced.
+*/
+func ParseNormalizerVersion(version string) (*Normalizer, error) {
+	if version == "" {
+		return NewNormalizer()
+	}
+	items := strings.SplitN(version, ":", 2)
+	if items[0] != "v"+NormalizerVersion || len(items) != 2 {
+		return nil, fmt.Errorf("%s: unsupported normalization version (supported version is v%s)", version, NormalizerVersion)
+	}
+	return NewNormalizer(strings.Split(items[1], ",")...)
+}
+
+/*
+Version returns the version string of the normalizer, such as "v3:copyright,lowercase".
+The normalizer without steps returns the empty string.
+*/
+func (normalizer *Normalizer) Version() string {
+	if normalizer == nil || len(normalizer.steps) == 0 {
+		return ""
+	}
+	names := []string{}
+	for _, step := range normalizer.steps {
+		names = append(names, step.Name)
+	}
+	return fmt.Sprintf("v%s:%s", NormalizerVersion, strings.Join(names, ","))
+}
+
+/*
+Normalize applies the steps of the normalizer to the given data, and collapses the whitespaces.
+*/
+func (normalizer *Normalizer) Normalize(data []byte) string {
+	text := string(data)
+	if normalizer == nil {
+		return Normalize(data)
+	}
+	for _, step := range normalizer.steps {
+		text = step.apply(text)
+	}
+	return Normalize([]byte(text))
+}
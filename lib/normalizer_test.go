@@ -0,0 +1,73 @@
+package lib
+
+import "testing"
+
+func TestNormalizer(t *testing.T) {
+	testdata := []struct {
+		steps []string
+		given string
+		wont  string
+	}{
+		{[]string{"none"}, "Copyright (c) 2020 Foo\nThe  Licence", "Copyright (c) 2020 Foo The Licence"},
+		{[]string{"copyright"}, "Copyright (c) 2020 Foo\n(C) 2021 Bar\n© Baz\nThe Licence", "The Licence"},
+		{[]string{"list-numbering"}, "1. first\n  (a) second\n  * third\n  2.1. fourth\nnot 3. list", "first second third fourth not 3. list"},
+		{[]string{"lowercase"}, "The MIT License", "the mit license"},
+		{[]string{"punctuation"}, "“quoted” ‘single’ — dash – en ``tex''", "\"quoted\" 'single' - dash - en \"tex\""},
+		{[]string{"http"}, "see HTTPS://example.com and https://example.org", "see http://example.com and http://example.org"},
+		{[]string{"equivalent-words"}, "the licence, sub license, and whilst licensed", "the license, sublicense, and while licensed"},
+		{[]string{"equivalent-words"}, "wilful misconduct, willful misconduct", "willful misconduct, willful misconduct"},
+		{[]string{"all"}, "Copyright 2020 Foo\n1. The Licence at https://example.com", "the license at http://example.com"},
+	}
+	for _, td := range testdata {
+		normalizer, err := NewNormalizer(td.steps...)
+		if err != nil {
+			t.Errorf("NewNormalizer(%v) failed: %s", td.steps, err.Error())
+			continue
+		}
+		if got := normalizer.Normalize([]byte(td.given)); got != td.wont {
+			t.Errorf("Normalize by %v did not match, wont \"%s\", got \"%s\"", td.steps, td.wont, got)
+		}
+	}
+}
+
+func TestNormalizerVersion(t *testing.T) {
+	testdata := []struct {
+		steps       []string
+		wontVersion string
+	}{
+		{[]string{}, ""},
+		{[]string{"none"}, ""},
+		{[]string{"lowercase", "copyright"}, "v3:copyright,lowercase"},
+		{[]string{"all"}, "v3:copyright,list-numbering,lowercase,punctuation,http,equivalent-words"},
+	}
+	for _, td := range testdata {
+		normalizer, _ := NewNormalizer(td.steps...)
+		version := normalizer.Version()
+		if version != td.wontVersion {
+			t.Errorf("Version of %v did not match, wont %s, got %s", td.steps, td.wontVersion, version)
+		}
+		parsed, err := ParseNormalizerVersion(version)
+		if err != nil || parsed.Version() != version {
+			t.Errorf("ParseNormalizerVersion(%s) did not match, got %v, %v", version, parsed, err)
+		}
+	}
+}
+
+func TestInvalidNormalizer(t *testing.T) {
+	if _, err := NewNormalizer("unknown"); err == nil {
+		t.Errorf("NewNormalizer(unknown) should be failed")
+	}
+	for _, version := range []string{"v1:lowercase", "v2:lowercase", "lowercase", "v3:unknown"} {
+		if _, err := ParseNormalizerVersion(version); err == nil {
+			t.Errorf("ParseNormalizerVersion(%s) should be failed", version)
+		}
+	}
+}
+
+func TestNormalizeStepNames(t *testing.T) {
+	names := NormalizeStepNames()
+	normalizer, _ := NewNormalizer(names...)
+	if len(names) != len(NormalizeSteps()) || normalizer.Version() != DefaultNormalizer().Version() {
+		t.Errorf("NormalizeStepNames did not match, got %v", names)
+	}
+}
@@ -15,6 +15,7 @@ LicenseMeta shows meta information of license of SPDX.
 type LicenseMeta struct {
 	Names       *Names   `json:"name"`
 	OsiApproved bool     `json:"osi-approved"`
+	FsfLibre    bool     `json:"fsf-libre,omitempty"`
 	Deprecated  bool     `json:"deprecated"`
 	Urls        []string `json:"urls"`
 }
@@ -31,22 +32,36 @@ func (lm *LicenseMeta) String() string {
 	return fmt.Sprintf("%s (%s), OSI: %v, Deprecated: %v", lm.Names.ShortName, lm.Names.FullName, lm.OsiApproved, lm.Deprecated)
 }
 
-func createMeta(root *xmlpath.Node) *LicenseMeta {
+func createMeta(root *xmlpath.Node, element string) *LicenseMeta {
+	base := "/SPDXLicenseCollection/" + element
 	meta := new(LicenseMeta)
-	meta.OsiApproved = isTrue(root, "/SPDXLicenseCollection/license/@isOsiApproved")
-	meta.Deprecated = isTrue(root, "/SPDXLicenseCollection/license/@isDeprecated")
-	meta.Urls = stringSlice(root, "/SPDXLicenseCollection/license/crossRefs/crossRef")
+	meta.OsiApproved = isTrue(root, base+"/@isOsiApproved")
+	meta.Deprecated = isTrue(root, base+"/@isDeprecated")
+	meta.Urls = stringSlice(root, base+"/crossRefs/crossRef")
 	meta.Names = new(Names)
-	meta.Names.ShortName = findString(root, "/SPDXLicenseCollection/license/@licenseId")
-	meta.Names.FullName = findString(root, "/SPDXLicenseCollection/license/@name")
+	meta.Names.ShortName = findString(root, base+"/@licenseId")
+	meta.Names.FullName = findString(root, base+"/@name")
 	// fmt.Printf("\"%s\",\"%s\",%v,%v,%v\n", meta.Names.ShortName, meta.Names.FullName, meta.OsiApproved, meta.Deprecated, strings.Join(meta.Urls, ","))
 	return meta
 }
 
 /*
 ReadSPDX reads license data from SPDX xml file and returns meta information of license and lisense terms.
+The terms keep the line breaks for the line-based steps of Normalizer.
 */
 func ReadSPDX(path string) (*LicenseMeta, string, error) {
+	return readSPDXImpl(path, "license")
+}
+
+/*
+ReadSPDXException reads license exception data from SPDX xml file (e.g., src/exceptions/Classpath-exception-2.0.xml),
+and returns meta information of the exception and its terms.
+*/
+func ReadSPDXException(path string) (*LicenseMeta, string, error) {
+	return readSPDXImpl(path, "exception")
+}
+
+func readSPDXImpl(path, element string) (*LicenseMeta, string, error) {
 	reader, err := os.Open(path)
 	if err != nil {
 		return nil, "", err
@@ -56,10 +71,13 @@ func ReadSPDX(path string) (*LicenseMeta, string, error) {
 	if err != nil {
 		return nil, "", err
 	}
-	meta := createMeta(root)
-	data := stripHTML(findString(root, "/SPDXLicenseCollection/license/text"))
+	meta := createMeta(root, element)
+	if meta.Names.ShortName == "" {
+		return nil, "", fmt.Errorf("%s: %s not found", path, element)
+	}
+	data := stripHTML(findString(root, "/SPDXLicenseCollection/"+element+"/text"))
 
-	return meta, Normalize([]byte(data)), nil
+	return meta, strings.TrimSpace(data), nil
 }
 
 func stringSlice(root *xmlpath.Node, xpath string) []string {
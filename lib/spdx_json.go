@@ -0,0 +1,185 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+/*
+SPDXLicense shows a license read from the SPDX license list, its meta information and its terms.
+Text keeps the line breaks for the line-based steps of Normalizer.
+*/
+type SPDXLicense struct {
+	Meta *LicenseMeta
+	Text string
+}
+
+/*
+SPDXLicenseList shows the licenses and the license exceptions read from the SPDX license list, and its version.
+*/
+type SPDXLicenseList struct {
+	Version    string
+	Licenses   []*SPDXLicense
+	Exceptions []*SPDXLicense
+}
+
+type jsonLicenseList struct {
+	Version  string         `json:"licenseListVersion"`
+	Licenses []*jsonLicense `json:"licenses"`
+}
+
+type jsonExceptionList struct {
+	Exceptions []*jsonException `json:"exceptions"`
+}
+
+type jsonException struct {
+	ID         string   `json:"licenseExceptionId"`
+	Name       string   `json:"name"`
+	Deprecated bool     `json:"isDeprecatedLicenseId"`
+	SeeAlso    []string `json:"seeAlso"`
+	Text       string   `json:"licenseExceptionText"`
+	Template   string   `json:"licenseExceptionTemplate"`
+}
+
+func (je *jsonException) meta() *LicenseMeta {
+	return &LicenseMeta{
+		Names:      &Names{ShortName: je.ID, FullName: je.Name},
+		Deprecated: je.Deprecated,
+		Urls:       je.SeeAlso,
+	}
+}
+
+type jsonLicense struct {
+	ID          string   `json:"licenseId"`
+	Name        string   `json:"name"`
+	OsiApproved bool     `json:"isOsiApproved"`
+	FsfLibre    bool     `json:"isFsfLibre"`
+	Deprecated  bool     `json:"isDeprecatedLicenseId"`
+	SeeAlso     []string `json:"seeAlso"`
+	Text        string   `json:"licenseText"`
+	Template    string   `json:"standardLicenseTemplate"`
+}
+
+func (jl *jsonLicense) meta() *LicenseMeta {
+	return &LicenseMeta{
+		Names:       &Names{ShortName: jl.ID, FullName: jl.Name},
+		OsiApproved: jl.OsiApproved,
+		FsfLibre:    jl.FsfLibre,
+		Deprecated:  jl.Deprecated,
+		Urls:        jl.SeeAlso,
+	}
+}
+
+/*
+FindSPDXJSONDir finds the directory containing licenses.json of SPDX license-list-data from the given path.
+The given path is the json directory itself, or the root directory of license-list-data (e.g., the extracted release tarball).
+*/
+func FindSPDXJSONDir(path string) (string, bool) {
+	candidates := []string{path, filepath.Join(path, "json")}
+	for _, candidate := range candidates {
+		stat, err := os.Stat(filepath.Join(candidate, "licenses.json"))
+		if err == nil && stat.Mode().IsRegular() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+/*
+ReadSPDXJSON reads the licenses from the json directory of SPDX license-list-data.
+The directory must contain licenses.json and details/*.json.
+If the directory contains exceptions.json, the license exceptions are also read from exceptions/*.json.
+*/
+func ReadSPDXJSON(dir string) (*SPDXLicenseList, error) {
+	list := &jsonLicenseList{}
+	if err := readJSON(filepath.Join(dir, "licenses.json"), list); err != nil {
+		return nil, err
+	}
+	results := &SPDXLicenseList{Version: list.Version, Licenses: []*SPDXLicense{}, Exceptions: []*SPDXLicense{}}
+	for _, item := range list.Licenses {
+		license, err := readSPDXJSONDetail(dir, item)
+		if err != nil {
+			return nil, err
+		}
+		results.Licenses = append(results.Licenses, license)
+	}
+	exceptions, err := readSPDXJSONExceptions(dir)
+	if err != nil {
+		return nil, err
+	}
+	results.Exceptions = exceptions
+	return results, nil
+}
+
+func readSPDXJSONExceptions(dir string) ([]*SPDXLicense, error) {
+	path := filepath.Join(dir, "exceptions.json")
+	if _, err := os.Stat(path); err != nil {
+		return []*SPDXLicense{}, nil
+	}
+	list := &jsonExceptionList{}
+	if err := readJSON(path, list); err != nil {
+		return nil, err
+	}
+	results := []*SPDXLicense{}
+	for _, item := range list.Exceptions {
+		exception, err := readSPDXJSONExceptionDetail(dir, item)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, exception)
+	}
+	return results, nil
+}
+
+func readSPDXJSONExceptionDetail(dir string, item *jsonException) (*SPDXLicense, error) {
+	detail := &jsonException{}
+	if err := readJSON(filepath.Join(dir, "exceptions", item.ID+".json"), detail); err != nil {
+		return nil, err
+	}
+	return newSPDXLicense(item.meta(), detail.Text, detail.Template)
+}
+
+func readSPDXJSONDetail(dir string, item *jsonLicense) (*SPDXLicense, error) {
+	detail := &jsonLicense{}
+	if err := readJSON(filepath.Join(dir, "details", item.ID+".json"), detail); err != nil {
+		return nil, err
+	}
+	return newSPDXLicense(item.meta(), detail.Text, detail.Template)
+}
+
+func newSPDXLicense(meta *LicenseMeta, text, template string) (*SPDXLicense, error) {
+	if text == "" {
+		text = templateToText(template)
+	}
+	if text == "" {
+		return nil, fmt.Errorf("%s: license text not found", meta.Names.ShortName)
+	}
+	return &SPDXLicense{Meta: meta, Text: strings.TrimSpace(text)}, nil
+}
+
+func readJSON(path string, value interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("%s: %s", path, err.Error())
+	}
+	return nil
+}
+
+var templateVarPattern = regexp.MustCompile(`<<var;[^>]*?original="([^"]*)"[^>]*>>`)
+var templateTagPattern = regexp.MustCompile(`<<[^>]*>>`)
+
+/*
+templateToText converts the SPDX license template into the plain text by replacing variables to their original texts.
+*/
+func templateToText(template string) string {
+	text := templateVarPattern.ReplaceAllString(template, "$1")
+	return templateTagPattern.ReplaceAllString(text, "")
+}
@@ -0,0 +1,67 @@
+package lib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindSPDXJSONDir(t *testing.T) {
+	testdata := []struct {
+		path     string
+		wontPath string
+		wontFlag bool
+	}{
+		{"testdata/json", "testdata/json", true},
+		{"testdata", "testdata/json", true},
+		{"testdata/json/details", "", false},
+	}
+	for _, td := range testdata {
+		gotPath, gotFlag := FindSPDXJSONDir(td.path)
+		if gotPath != td.wontPath || gotFlag != td.wontFlag {
+			t.Errorf("FindSPDXJSONDir(%s) did not match, wont (%s, %v), got (%s, %v)", td.path, td.wontPath, td.wontFlag, gotPath, gotFlag)
+		}
+	}
+}
+
+func TestReadSPDXJSON(t *testing.T) {
+	list, err := ReadSPDXJSON("testdata/json")
+	if err != nil {
+		t.Fatalf("ReadSPDXJSON failed: %s", err.Error())
+	}
+	if len(list.Exceptions) != 1 || list.Exceptions[0].Meta.Names.ShortName != "Classpath-exception-2.0" {
+		t.Errorf("exceptions did not match, wont [Classpath-exception-2.0], got %v", list.Exceptions)
+	}
+	if list.Version != "3.9" {
+		t.Errorf("license list version did not match, wont 3.9, got %s", list.Version)
+	}
+	testdata := []struct {
+		shortName   string
+		osiApproved bool
+		fsfLibre    bool
+		wontText    string
+	}{
+		{"0BSD", true, false, "Permission to use, copy, modify, and/or distribute this software"},
+		{"MIT", true, true, "Permission is hereby granted, free of charge"},
+		{"WTFPL", false, true, "DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE Version 2, December 2004 Copyright"},
+	}
+	if len(list.Licenses) != len(testdata) {
+		t.Fatalf("license count did not match, wont %d, got %d", len(testdata), len(list.Licenses))
+	}
+	for i, td := range testdata {
+		license := list.Licenses[i]
+		if license.Meta.Names.ShortName != td.shortName || license.Meta.OsiApproved != td.osiApproved || license.Meta.FsfLibre != td.fsfLibre {
+			t.Errorf("meta of %s did not match, got %s", td.shortName, license.Meta.String())
+		}
+		if !strings.Contains(Normalize([]byte(license.Text)), td.wontText) {
+			t.Errorf("text of %s did not contain \"%s\", got %s", td.shortName, td.wontText, license.Text)
+		}
+	}
+}
+
+func TestTemplateToText(t *testing.T) {
+	template := `<<beginOptional>>MIT License<<endOptional>> <<var;name="copyright";original="Copyright (c) <year> <copyright holders>";match=".{0,5000}">> Permission`
+	wontText := "MIT License Copyright (c) <year> <copyright holders> Permission"
+	if gotText := templateToText(template); gotText != wontText {
+		t.Errorf("templateToText did not match, wont %s, got %s", wontText, gotText)
+	}
+}
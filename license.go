@@ -6,10 +6,13 @@ import (
 
 /*
 License shows the license data for identifying.
+members holds the licenses built by each member algorithm, if the license is built by the ensemble algorithm.
 */
 type License struct {
 	Name        string         `json:"license-name"`
 	Frequencies map[string]int `json:"frequencies"`
+	Signature   []uint32       `json:"minhash,omitempty"`
+	members     map[string]*License
 }
 
 func newLicense(name string, data map[string]int) *License {
@@ -57,3 +60,48 @@ func (license *License) magnitude() float64 {
 	}
 	return math.Sqrt(float64(sum))
 }
+
+/*
+containment calculates how much of other is contained in the license, by range of 0.0 to 1.0.
+This value is 1.0 if all of terms of other appear in the license, even if the license has much more terms.
+*/
+func (license *License) containment(other *License) float64 {
+	sum := 0
+	for key, count := range other.Frequencies {
+		sum += minInt(count, license.Frequencies[key])
+	}
+	total := other.total()
+	if total == 0 {
+		return 0
+	}
+	return float64(sum) / float64(total)
+}
+
+/*
+jaccard calculates the weighted Jaccard similarity between license and other, by range of 0.0 to 1.0.
+*/
+func (license *License) jaccard(other *License) float64 {
+	minSum, maxSum := 0, 0
+	for key := range extractKeys(license, other) {
+		minSum += minInt(license.Frequencies[key], other.Frequencies[key])
+		maxSum += maxInt(license.Frequencies[key], other.Frequencies[key])
+	}
+	if maxSum == 0 {
+		return 0
+	}
+	return float64(minSum) / float64(maxSum)
+}
+
+func maxInt(value1, value2 int) int {
+	if value1 > value2 {
+		return value1
+	}
+	return value2
+}
+
+func minInt(value1, value2 int) int {
+	if value1 < value2 {
+		return value1
+	}
+	return value2
+}
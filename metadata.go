@@ -0,0 +1,36 @@
+package lioss
+
+import "github.com/tamada/lioss/lib"
+
+/*
+PutMeta stores the SPDX metadata (e.g., the full name, OSI approval, and the reference URLs) of the given license into the database.
+*/
+func (db *Database) PutMeta(licenseName string, meta *lib.LicenseMeta) {
+	if db.Metadata == nil {
+		db.Metadata = map[string]*lib.LicenseMeta{}
+	}
+	db.Metadata[licenseName] = meta
+}
+
+/*
+Meta returns the SPDX metadata of the given license, and nil if the database has no metadata of the license.
+*/
+func (db *Database) Meta(licenseName string) *lib.LicenseMeta {
+	return db.Metadata[licenseName]
+}
+
+/*
+mergeMetadata merges the metadata of both databases, and the metadata of the first one have priority.
+*/
+func mergeMetadata(metadata, other map[string]*lib.LicenseMeta) map[string]*lib.LicenseMeta {
+	if len(metadata) == 0 && len(other) == 0 {
+		return nil
+	}
+	results := map[string]*lib.LicenseMeta{}
+	for _, source := range []map[string]*lib.LicenseMeta{other, metadata} {
+		for name, meta := range source {
+			results[name] = meta
+		}
+	}
+	return results
+}
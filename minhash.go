@@ -0,0 +1,158 @@
+package lioss
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"math/rand"
+)
+
+/*
+MinHashParameters shows the parameters of MinHash signatures and locality-sensitive hashing (LSH) index.
+The length of signatures is Bands * Rows, and two licenses become candidates of each other
+if all of the rows in at least one band are the same.
+*/
+type MinHashParameters struct {
+	Bands int   `json:"bands"`
+	Rows  int   `json:"rows"`
+	Seed  int64 `json:"seed"`
+}
+
+/*
+DefaultMinHashParameters returns the default parameters of MinHash (32 bands of 4 rows).
+The licenses whose Jaccard similarity of terms is 0.5 become candidates with the probability of about 0.87,
+and those with 0.3 become candidates with the probability of about 0.23.
+*/
+func DefaultMinHashParameters() *MinHashParameters {
+	return &MinHashParameters{Bands: 32, Rows: 4, Seed: 1}
+}
+
+type minHasher struct {
+	params       *MinHashParameters
+	coefficients [][2]uint64
+}
+
+func newMinHasher(params *MinHashParameters) *minHasher {
+	random := rand.New(rand.NewSource(params.Seed))
+	coefficients := make([][2]uint64, params.Bands*params.Rows)
+	for i := range coefficients {
+		coefficients[i] = [2]uint64{random.Uint64() | 1, random.Uint64()}
+	}
+	return &minHasher{params: params, coefficients: coefficients}
+}
+
+func hashTerm(term string) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(term))
+	return hash.Sum64()
+}
+
+/*
+signature computes the MinHash signature of the given license, which the terms are the keys of Frequencies.
+*/
+func (hasher *minHasher) signature(license *License) []uint32 {
+	signature := make([]uint32, len(hasher.coefficients))
+	for i := range signature {
+		signature[i] = math.MaxUint32
+	}
+	for term := range license.Frequencies {
+		value := hashTerm(term)
+		for i, c := range hasher.coefficients {
+			if hashed := uint32((c[0]*value + c[1]) >> 32); hashed < signature[i] {
+				signature[i] = hashed
+			}
+		}
+	}
+	return signature
+}
+
+func (hasher *minHasher) bandKeys(signature []uint32) []uint64 {
+	keys := make([]uint64, hasher.params.Bands)
+	buffer := make([]byte, 4)
+	for band := range keys {
+		hash := fnv.New64a()
+		for _, value := range signature[band*hasher.params.Rows : (band+1)*hasher.params.Rows] {
+			binary.LittleEndian.PutUint32(buffer, value)
+			hash.Write(buffer)
+		}
+		keys[band] = hash.Sum64()
+	}
+	return keys
+}
+
+func (hasher *minHasher) isValidSignature(signature []uint32) bool {
+	return len(signature) == len(hasher.coefficients)
+}
+
+/*
+BuildMinHash computes MinHash signatures of all of licenses in the database with the given parameters,
+and stores the signatures and the parameters into the database.
+*/
+func (db *Database) BuildMinHash(params *MinHashParameters) {
+	hasher := newMinHasher(params)
+	for _, licenses := range db.Data {
+		for _, license := range licenses {
+			license.Signature = hasher.signature(license)
+		}
+	}
+	db.MinHash = params
+}
+
+/*
+IndexableAlgorithm is the optional interface of Algorithm, which shows whether the identifier may shortlist the candidates by the MinHash index.
+The index finds the licenses sharing many terms (high Jaccard similarity) with the given license,
+therefore, the algorithms scoring the license embedded in a larger file high (e.g., containment, and BM25) must not use the index.
+The algorithms not implementing this interface are compared with all of licenses in the database.
+*/
+type IndexableAlgorithm interface {
+	Indexable() bool
+}
+
+func isIndexable(algorithm Algorithm) bool {
+	indexable, ok := algorithm.(IndexableAlgorithm)
+	return ok && indexable.Indexable()
+}
+
+/*
+lshIndex is the locality-sensitive hashing index for finding candidate licenses.
+lshIndex is read only after built, therefore, it is safe for concurrent use.
+*/
+type lshIndex struct {
+	hasher  *minHasher
+	buckets []map[uint64][]*License
+}
+
+func buildIndex(params *MinHashParameters, licenses []*License) *lshIndex {
+	hasher := newMinHasher(params)
+	index := &lshIndex{hasher: hasher, buckets: make([]map[uint64][]*License, params.Bands)}
+	for i := range index.buckets {
+		index.buckets[i] = map[uint64][]*License{}
+	}
+	for _, license := range licenses {
+		signature := license.Signature
+		if !hasher.isValidSignature(signature) {
+			signature = hasher.signature(license)
+		}
+		for band, key := range hasher.bandKeys(signature) {
+			index.buckets[band][key] = append(index.buckets[band][key], license)
+		}
+	}
+	return index
+}
+
+/*
+candidates returns the licenses sharing at least one band with the given license.
+*/
+func (index *lshIndex) candidates(license *License) []*License {
+	found := map[*License]bool{}
+	results := []*License{}
+	for band, key := range index.hasher.bandKeys(index.hasher.signature(license)) {
+		for _, candidate := range index.buckets[band][key] {
+			if !found[candidate] {
+				found[candidate] = true
+				results = append(results, candidate)
+			}
+		}
+	}
+	return results
+}
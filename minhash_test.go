@@ -0,0 +1,154 @@
+package lioss
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func buildMinHashTestDB(t *testing.T, algorithm Algorithm) *Database {
+	db := NewDatabase()
+	infos, _ := ioutil.ReadDir("data/misc")
+	for _, info := range infos {
+		reader, _ := os.Open(filepath.Join("data/misc", info.Name()))
+		license, err := algorithm.Parse(reader, info.Name())
+		reader.Close()
+		if err != nil {
+			t.Fatalf("parse %s failed: %s", info.Name(), err.Error())
+		}
+		db.Put(algorithm.String(), license)
+	}
+	db.BuildMinHash(DefaultMinHashParameters())
+	return db
+}
+
+func TestSignatureIsDeterministic(t *testing.T) {
+	license := newLicense("license", map[string]int{"a": 1, "b": 2, "c": 3})
+	params := DefaultMinHashParameters()
+	sig1 := newMinHasher(params).signature(license)
+	sig2 := newMinHasher(params).signature(license)
+	if !reflect.DeepEqual(sig1, sig2) {
+		t.Errorf("signatures did not match, %v, %v", sig1, sig2)
+	}
+	if len(sig1) != params.Bands*params.Rows {
+		t.Errorf("length of signature did not match, wont %d, got %d", params.Bands*params.Rows, len(sig1))
+	}
+	other := newMinHasher(&MinHashParameters{Bands: params.Bands, Rows: params.Rows, Seed: 2}).signature(license)
+	if reflect.DeepEqual(sig1, other) {
+		t.Errorf("signatures with different seeds should be different")
+	}
+}
+
+func TestCandidatesContainSameLicense(t *testing.T) {
+	algorithm, _ := NewAlgorithm("5gram")
+	db := buildMinHashTestDB(t, algorithm)
+	index := buildIndex(db.MinHash, db.Entries("5gram"))
+	for _, license := range db.Entries("5gram") {
+		found := false
+		for _, candidate := range index.candidates(license) {
+			if candidate == license {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("candidates of %s did not contain itself", license.Name)
+		}
+	}
+}
+
+func TestIdentifyWithIndex(t *testing.T) {
+	testdata := []struct {
+		algorithm string
+		path      string
+		wontName  string
+	}{
+		{"5gram", "data/misc/Apache-License-2.0", "Apache-License-2.0"},
+		{"5gram", "data/misc/BSD-3-Clause", "BSD-3-Clause"},
+		{"wordfreq", "data/misc/GPLv2.0", "GPLv2.0"},
+		{"tfidf", "data/misc/MIT", "MIT"},
+	}
+	for _, td := range testdata {
+		algorithm, _ := NewAlgorithm(td.algorithm)
+		identifier, _ := NewIdentifier(td.algorithm, 0.75, buildMinHashTestDB(t, algorithm))
+		if identifier.index == nil {
+			t.Fatalf("index was not built")
+		}
+		reader, _ := os.Open(td.path)
+		license, _ := identifier.Comparator.Parse(reader, "LICENSE")
+		reader.Close()
+		indexed, _ := identifier.identify(context.Background(), license)
+		identifier.Exhaustive = true
+		exhaustive, _ := identifier.identify(context.Background(), license)
+		if len(indexed) == 0 || len(exhaustive) == 0 {
+			t.Errorf("%s by %s: results were empty, indexed: %v, exhaustive: %v", td.path, td.algorithm, indexed, exhaustive)
+			continue
+		}
+		if indexed[0].Name != td.wontName || exhaustive[0].Name != td.wontName {
+			t.Errorf("%s by %s: top results did not match, wont %s, got %s (indexed), %s (exhaustive)", td.path, td.algorithm, td.wontName, indexed[0].Name, exhaustive[0].Name)
+		}
+	}
+}
+
+func TestMergeRebuildsSignatures(t *testing.T) {
+	db1 := NewDatabase()
+	db1.Put("1gram", newLicense("l1", map[string]int{"a": 1}))
+	db1.BuildMinHash(DefaultMinHashParameters())
+	db2 := NewDatabase()
+	db2.Put("1gram", newLicense("l2", map[string]int{"b": 1}))
+	db2.BuildMinHash(&MinHashParameters{Bands: 2, Rows: 2, Seed: 3})
+	merged := db1.Merge(db2)
+	if merged.MinHash == nil {
+		t.Fatalf("merged database has no MinHash parameters")
+	}
+	hasher := newMinHasher(merged.MinHash)
+	for _, license := range merged.Entries("1gram") {
+		if !hasher.isValidSignature(license.Signature) {
+			t.Errorf("signature of %s is not valid for merged parameters", license.Name)
+		}
+	}
+}
+
+func TestIdentifyEmbeddedLicense(t *testing.T) {
+	apache, _ := ioutil.ReadFile("data/misc/Apache-License-2.0")
+	mit, _ := ioutil.ReadFile("data/misc/MIT")
+	text := string(apache) + "\n\nThe bundled library is distributed under the following license.\n\n" + string(mit)
+	for _, algorithmName := range []string{"3shingle-containment", "ensemble:3shingle-containment"} {
+		algorithm, _ := NewAlgorithm("3shingle-containment")
+		identifier, err := NewIdentifier(algorithmName, 0.9, buildMinHashTestDB(t, algorithm))
+		if err != nil {
+			t.Fatalf("NewIdentifier(%s) failed: %s", algorithmName, err.Error())
+		}
+		result, err := identifier.IdentifyText(text)
+		if err != nil {
+			t.Fatalf("%s: IdentifyText failed: %s", algorithmName, err.Error())
+		}
+		if !containsResult(result.Results, "MIT") || !containsResult(result.Results, "Apache-License-2.0") {
+			t.Errorf("%s: embedded licenses were not found, got %v", algorithmName, result.Results)
+		}
+	}
+}
+
+func containsResult(results []*Result, name string) bool {
+	for _, result := range results {
+		if result.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCandidatesWithoutIndexedCandidates(t *testing.T) {
+	algorithm, _ := NewAlgorithm("5gram")
+	identifier, _ := NewIdentifier("5gram", 0.75, buildMinHashTestDB(t, algorithm))
+	license, _ := identifier.Comparator.Parse(strings.NewReader("zzzzz yyyyy xxxxx"), "LICENSE")
+	if len(identifier.index.candidates(license)) != 0 {
+		t.Fatalf("index found candidates for the unrelated text")
+	}
+	if got, wont := len(identifier.candidates(license)), len(identifier.Entries()); got != wont {
+		t.Errorf("candidates without indexed candidates did not fall back to all of licenses, wont %d, got %d", wont, got)
+	}
+}
@@ -28,6 +28,13 @@ func (ngram *nGram) Prepare(db *Database) error {
 	return nil
 }
 
+/*
+Indexable of NGram returns true, since the similar licenses by n-gram share many terms.
+*/
+func (ngram *nGram) Indexable() bool {
+	return true
+}
+
 func (ngram *nGram) String() string {
 	return fmt.Sprintf("%dgram", ngram.nValue)
 }
@@ -0,0 +1,78 @@
+package lioss
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/tamada/lioss/lib"
+)
+
+/*
+Normalizer returns the normalizer which the database was built by.
+This method returns an error if the normalization version of the database is not supported.
+*/
+func (db *Database) Normalizer() (*lib.Normalizer, error) {
+	return lib.ParseNormalizerVersion(db.Normalization)
+}
+
+/*
+CheckNormalization returns an error if the given database was built by the different normalization from the database.
+The databases without licenses are compatible with any normalization.
+*/
+func (db *Database) CheckNormalization(other *Database) error {
+	if db.Normalization == other.Normalization || db.isEmpty() || other.isEmpty() {
+		return nil
+	}
+	return fmt.Errorf("normalization mismatch: \"%s\" and \"%s\"", db.Normalization, other.Normalization)
+}
+
+func (db *Database) isEmpty() bool {
+	for _, licenses := range db.Data {
+		if len(licenses) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+BuildNormalizer returns the normalizer for building the database from the given steps (comma separated), and records its version into the database.
+In update mode, the normalizer of the database is used unless the steps are given by the user,
+and the steps different from the normalization of the database are an error.
+*/
+func (db *Database) BuildNormalizer(steps string, update, given bool) (*lib.Normalizer, error) {
+	if update && !given {
+		return db.Normalizer()
+	}
+	normalizer, err := lib.NewNormalizer(strings.Split(steps, ",")...)
+	if err != nil {
+		return nil, err
+	}
+	if update && db.Normalization != normalizer.Version() {
+		return nil, fmt.Errorf("normalization mismatch: the database uses \"%s\", but \"%s\" is given", db.Normalization, normalizer.Version())
+	}
+	db.Normalization = normalizer.Version()
+	return normalizer, nil
+}
+
+func mergeNormalization(db, other *Database) string {
+	if db.isEmpty() {
+		return other.Normalization
+	}
+	return db.Normalization
+}
+
+/*
+ParseLicense normalizes the data from the given reader by the given normalizer, and parses it by the given algorithm.
+The database builders and Identifier use this function, therefore, the same normalization is applied in both of them.
+*/
+func ParseLicense(normalizer *lib.Normalizer, algorithm Algorithm, reader io.Reader, licenseName string) (*License, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return algorithm.Parse(bytes.NewReader([]byte(normalizer.Normalize(data))), licenseName)
+}
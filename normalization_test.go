@@ -0,0 +1,122 @@
+package lioss
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/tamada/lioss/lib"
+)
+
+func TestParseLicense(t *testing.T) {
+	algorithm, _ := NewAlgorithm("wordfreq")
+	license1, _ := ParseLicense(lib.DefaultNormalizer(), algorithm, strings.NewReader("Copyright 2020 Foo\nThe Licence"), "license1")
+	license2, _ := ParseLicense(lib.DefaultNormalizer(), algorithm, strings.NewReader("Copyright 1999 Bar\nthe license"), "license2")
+	if !isSameFrequencies(license1.Frequencies, license2.Frequencies) {
+		t.Errorf("normalized licenses did not match, %v, %v", license1.Frequencies, license2.Frequencies)
+	}
+}
+
+func TestCheckNormalization(t *testing.T) {
+	newDB := func(normalization string, empty bool) *Database {
+		db := NewDatabase()
+		db.Normalization = normalization
+		if !empty {
+			db.Put("wordfreq", newLicense("MIT", map[string]int{"mit": 1}))
+		}
+		return db
+	}
+	testdata := []struct {
+		db1       *Database
+		db2       *Database
+		errorFlag bool
+	}{
+		{newDB("v3:lowercase", false), newDB("v3:lowercase", false), false},
+		{newDB("v3:lowercase", false), newDB("", false), true},
+		{newDB("", true), newDB("v3:lowercase", false), false},
+	}
+	for _, td := range testdata {
+		if err := td.db1.CheckNormalization(td.db2); (err != nil) != td.errorFlag {
+			t.Errorf("CheckNormalization(%s, %s) did not match, wont error %v, got %v", td.db1.Normalization, td.db2.Normalization, td.errorFlag, err)
+		}
+	}
+	if merged := newDB("", true).Merge(newDB("v3:lowercase", false)); merged.Normalization != "v3:lowercase" {
+		t.Errorf("normalization of merged database did not match, got %s", merged.Normalization)
+	}
+}
+
+func TestBuildNormalizer(t *testing.T) {
+	lowercase, _ := lib.NewNormalizer("lowercase")
+	testdata := []struct {
+		normalization string
+		steps         string
+		update        bool
+		given         bool
+		errorFlag     bool
+		wontVersion   string
+	}{
+		{"", "lowercase,copyright", false, true, false, "v3:copyright,lowercase"},
+		{lowercase.Version(), "all", true, false, false, lowercase.Version()},
+		{lowercase.Version(), "lowercase", true, true, false, lowercase.Version()},
+		{lowercase.Version(), "all", true, true, true, ""},
+		{"", "unknown", false, true, true, ""},
+	}
+	for _, td := range testdata {
+		db := NewDatabase()
+		db.Normalization = td.normalization
+		normalizer, err := db.BuildNormalizer(td.steps, td.update, td.given)
+		if (err != nil) != td.errorFlag {
+			t.Errorf("BuildNormalizer(%s, %v, %v) did not match, wont error %v, got %v", td.steps, td.update, td.given, td.errorFlag, err)
+		}
+		if err == nil && (normalizer.Version() != td.wontVersion || db.Normalization != td.wontVersion) {
+			t.Errorf("BuildNormalizer(%s, %v, %v) did not match, wont %s, got %s (%s)", td.steps, td.update, td.given, td.wontVersion, normalizer.Version(), db.Normalization)
+		}
+	}
+}
+
+func TestIdentifierNormalizer(t *testing.T) {
+	db := NewDatabase()
+	db.Put("5gram", newLicense("license", map[string]int{"abcde": 1}))
+	db.Normalization = "v3:unknown"
+	if _, err := NewIdentifier("5gram", 0.75, db); err == nil {
+		t.Errorf("NewIdentifier should be failed for unsupported normalization")
+	}
+	db.Normalization = lib.DefaultNormalizer().Version()
+	identifier, err := NewIdentifier("5gram", 0.75, db)
+	if err != nil || identifier.Normalizer().Version() != db.Normalization {
+		t.Errorf("normalizer of identifier did not match, wont %s, got %v", db.Normalization, err)
+	}
+}
+
+func TestCopyrightsStrippedConsistently(t *testing.T) {
+	mit, _ := ioutil.ReadFile("data/misc/MIT")
+	entry := "Copyright (c) 2020 Haruaki Tamada\n\n" + string(mit)
+	target := "Copyright (c) 2021 Someone Else\n\n" + string(mit)
+	testdata := []struct {
+		normalization string
+		giveText      string
+		wontExact     bool
+	}{
+		{"all", entry, true},
+		{"all", target, true},
+		{"none", entry, true},
+		{"none", target, false},
+	}
+	for _, td := range testdata {
+		normalizer, _ := lib.NewNormalizer(td.normalization)
+		db := NewDatabase()
+		db.Normalization = normalizer.Version()
+		algorithm, _ := NewAlgorithm("5gram")
+		license, _ := ParseLicense(normalizer, algorithm, strings.NewReader(entry), "MIT")
+		db.Put("5gram", license)
+		identifier, _ := NewIdentifier("5gram", 0.5, db)
+		result, err := identifier.IdentifyText(td.giveText)
+		if err != nil || len(result.Results) == 0 {
+			t.Errorf("%s: identify failed: %v", td.normalization, err)
+			continue
+		}
+		if gotExact := result.Results[0].Probability > 0.9999; gotExact != td.wontExact {
+			t.Errorf("%s: exact match did not match, wont %v, got %f", td.normalization, td.wontExact, result.Results[0].Probability)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package lioss
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/tamada/lioss/lib"
+)
+
+/*
+UnknownLicense is the license name of the components whose licenses are not identified.
+*/
+const UnknownLicense = "Unknown"
+
+/*
+NoticeComponent shows a component (a license file of a project) in the notice.
+*/
+type NoticeComponent struct {
+	/*Name shows the name of the component, that is, the base name of the project.*/
+	Name string `json:"name"`
+	/*Path shows the id of the license file in the project.*/
+	Path string `json:"path,omitempty"`
+	/*License shows the identified license expression of the component.*/
+	License string `json:"license"`
+	/*Copyrights shows the copyright notices found in the license file.*/
+	Copyrights []*lib.Copyright `json:"copyrights,omitempty"`
+	/*LicenseText shows the original license text of the component.*/
+	LicenseText string `json:"license-text,omitempty"`
+	/*Notices shows the contents of NOTICE files in the project, which are attached to the first component of the project only.*/
+	Notices []string `json:"notices,omitempty"`
+}
+
+/*
+NoticeGroup shows the components grouped by the license.
+*/
+type NoticeGroup struct {
+	License    string             `json:"license"`
+	Components []*NoticeComponent `json:"components"`
+}
+
+/*
+Anchor returns the id of the group in the HTML notice, which replaces the whitespaces in the license expression with "-",
+e.g., "GPL-2.0-only-WITH-Classpath-exception-2.0".
+*/
+func (group *NoticeGroup) Anchor() string {
+	return strings.Join(strings.Fields(group.License), "-")
+}
+
+/*
+Notice shows the attribution notice (e.g., THIRD-PARTY-NOTICES) of the projects.
+*/
+type Notice struct {
+	Groups []*NoticeGroup `json:"groups"`
+}
+
+/*
+BuildNotice builds the notice from the identified results of projects.
+The projects of the results must not be closed, since the license texts, and NOTICE files are read from them.
+Each license file is grouped by the most probable license, and the license files with no results are grouped as UnknownLicense.
+*/
+func BuildNotice(results []*ProjectResult) (*Notice, error) {
+	groups := map[string]*NoticeGroup{}
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, fmt.Errorf("%s: %s", result.Project.BasePath(), result.Err.Error())
+		}
+		components, err := buildComponents(result)
+		if err != nil {
+			return nil, err
+		}
+		for _, component := range components {
+			putComponent(groups, component)
+		}
+	}
+	return &Notice{Groups: sortGroups(groups)}, nil
+}
+
+func putComponent(groups map[string]*NoticeGroup, component *NoticeComponent) {
+	group, ok := groups[component.License]
+	if !ok {
+		group = &NoticeGroup{License: component.License, Components: []*NoticeComponent{}}
+		groups[component.License] = group
+	}
+	group.Components = append(group.Components, component)
+}
+
+func sortGroups(groups map[string]*NoticeGroup) []*NoticeGroup {
+	results := []*NoticeGroup{}
+	for _, group := range groups {
+		sort.SliceStable(group.Components, func(i, j int) bool {
+			c1, c2 := group.Components[i], group.Components[j]
+			return c1.Name < c2.Name || (c1.Name == c2.Name && c1.Path < c2.Path)
+		})
+		results = append(results, group)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].License == UnknownLicense || results[j].License == UnknownLicense {
+			return results[j].License == UnknownLicense && results[i].License != UnknownLicense
+		}
+		return results[i].License < results[j].License
+	})
+	return results
+}
+
+func buildComponents(result *ProjectResult) ([]*NoticeComponent, error) {
+	name := filepath.Base(result.Project.BasePath())
+	notices, err := readNotices(result.Project)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Files) == 0 {
+		return []*NoticeComponent{{Name: name, License: UnknownLicense, Notices: notices}}, nil
+	}
+	components := []*NoticeComponent{}
+	for _, file := range result.Files {
+		text, err := readProjectFile(result.Project.LicenseFile(file.File.ID()))
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, &NoticeComponent{Name: name, Path: file.File.ID(), License: licenseOf(file.Results),
+			Copyrights: file.Copyrights, LicenseText: text})
+	}
+	components[0].Notices = notices
+	return components, nil
+}
+
+func licenseOf(results []*Result) string {
+	if len(results) == 0 {
+		return UnknownLicense
+	}
+	return results[0].Expression()
+}
+
+func readNotices(project Project) ([]string, error) {
+	finder, ok := project.(NoticeFinder)
+	if !ok {
+		return []string{}, nil
+	}
+	notices := []string{}
+	for _, id := range finder.NoticeIDs() {
+		text, err := readProjectFile(finder.NoticeFile(id))
+		if err != nil {
+			return nil, err
+		}
+		notices = append(notices, text)
+	}
+	return notices, nil
+}
+
+func readProjectFile(file LicenseFile, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", file.ID(), err.Error())
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+/*
+NoticeFormats returns the available formats of the notice, that is, "text", "markdown", and "html".
+*/
+func NoticeFormats() []string {
+	return []string{"text", "markdown", "html"}
+}
+
+/*
+Write writes the notice in the given format to the writer.
+The available formats are shown by NoticeFormats.
+*/
+func (notice *Notice) Write(writer io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case "text":
+		return texttemplate.Must(texttemplate.New("text").Parse(textNoticeTemplate)).Execute(writer, notice)
+	case "markdown":
+		return texttemplate.Must(texttemplate.New("markdown").Parse(markdownNoticeTemplate)).Execute(writer, notice)
+	case "html":
+		return htmltemplate.Must(htmltemplate.New("html").Parse(htmlNoticeTemplate)).Execute(writer, notice)
+	}
+	return fmt.Errorf("%s: unknown notice format", format)
+}
+
+const textNoticeTemplate = `THIRD-PARTY SOFTWARE NOTICES AND INFORMATION
+
+This software includes the following third-party components.
+{{range .Groups}}
+================================================================================
+{{.License}}
+================================================================================
+{{range .Components}}
+--------------------------------------------------------------------------------
+{{.Name}}{{if .Path}} ({{.Path}}){{end}}
+{{range .Copyrights}}{{.Statement}}
+{{end}}{{if .LicenseText}}
+{{.LicenseText}}
+{{end}}{{range .Notices}}
+NOTICE:
+{{.}}
+{{end}}{{end}}{{end}}`
+
+const markdownNoticeTemplate = `# Third-Party Software Notices and Information
+
+This software includes the following third-party components.
+{{range .Groups}}
+## {{.License}}
+{{range .Components}}
+### {{.Name}}
+{{if .Path}}
+* License file: ` + "`{{.Path}}`" + `
+{{end}}{{range .Copyrights}}* {{.Statement}}
+{{end}}{{if .LicenseText}}
+~~~text
+{{.LicenseText}}
+~~~
+{{end}}{{range .Notices}}
+#### NOTICE
+
+~~~text
+{{.}}
+~~~
+{{end}}{{end}}{{end}}`
+
+const htmlNoticeTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Third-Party Software Notices and Information</title>
+</head>
+<body>
+<h1>Third-Party Software Notices and Information</h1>
+<p>This software includes the following third-party components.</p>
+<ul>
+{{range .Groups}}<li><a href="#{{.Anchor}}">{{.License}}</a> ({{len .Components}})</li>
+{{end}}</ul>
+{{range .Groups}}<section id="{{.Anchor}}">
+<h2>{{.License}}</h2>
+{{range .Components}}<article>
+<h3>{{.Name}}</h3>
+{{if .Path}}<p>License file: <code>{{.Path}}</code></p>
+{{end}}{{if .Copyrights}}<ul>
+{{range .Copyrights}}<li>{{.Statement}}</li>
+{{end}}</ul>
+{{end}}{{if .LicenseText}}<pre>{{.LicenseText}}</pre>
+{{end}}{{range .Notices}}<h4>NOTICE</h4>
+<pre>{{.}}</pre>
+{{end}}</article>
+{{end}}</section>
+{{end}}</body>
+</html>
+`
@@ -0,0 +1,98 @@
+package lioss
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func buildTestNotice(t *testing.T, paths []string) *Notice {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	identifier, _ := NewIdentifier("5gram", 0.75, db)
+	projects := []Project{}
+	for _, path := range paths {
+		project, _ := NewProject(path)
+		defer project.Close()
+		projects = append(projects, project)
+	}
+	results, _ := identifier.IdentifyAll(context.Background(), projects, 1)
+	notice, err := BuildNotice(results)
+	if err != nil {
+		t.Fatalf("BuildNotice failed: %s", err.Error())
+	}
+	return notice
+}
+
+func TestBuildNotice(t *testing.T) {
+	notice := buildTestNotice(t, []string{"testdata/project4", "testdata/project3", "testdata/project1"})
+	wontLicenses := []string{"Apache-License-2.0", "MIT", "WTFPL", UnknownLicense}
+	if len(notice.Groups) != len(wontLicenses) {
+		t.Fatalf("size of groups did not match, wont %d, got %d", len(wontLicenses), len(notice.Groups))
+	}
+	for i, group := range notice.Groups {
+		if group.License != wontLicenses[i] {
+			t.Errorf("license of group %d did not match, wont %s, got %s", i, wontLicenses[i], group.License)
+		}
+	}
+	apache := notice.Groups[0].Components[0]
+	if apache.Name != "project3" || apache.Path != "license" || !strings.HasPrefix(apache.LicenseText, "Apache License") {
+		t.Errorf("apache component did not match, got %s (%s)", apache.Name, apache.Path)
+	}
+	if len(apache.Notices) != 1 || !strings.Contains(apache.Notices[0], "The Project3 Authors") {
+		t.Errorf("notices of apache component did not match, got %v", apache.Notices)
+	}
+	if mit := notice.Groups[1].Components[0]; mit.Name != "project3" || len(mit.Notices) != 0 {
+		t.Errorf("notices of project3 should be attached once, got %v in %s (%s)", mit.Notices, mit.Name, mit.Path)
+	}
+	wtfpl := notice.Groups[2].Components[0]
+	if len(wtfpl.Copyrights) != 1 || wtfpl.Copyrights[0].Holder != "author name <project_url>" {
+		t.Errorf("copyrights of wtfpl component did not match, got %v", wtfpl.Copyrights)
+	}
+	unknown := notice.Groups[3].Components[0]
+	if unknown.Name != "project4" || unknown.LicenseText != "" {
+		t.Errorf("unknown component did not match, got %s", unknown.Name)
+	}
+}
+
+func TestWriteNotice(t *testing.T) {
+	notice := buildTestNotice(t, []string{"testdata/project1"})
+	testdata := []struct {
+		giveFormat    string
+		wontContained []string
+		wontErr       bool
+	}{
+		{"text", []string{"THIRD-PARTY SOFTWARE NOTICES", "WTFPL", "project1 (LICENSE)", "Copyright (C) 2004 author name <project_url>"}, false},
+		{"markdown", []string{"## WTFPL", "### project1", "* Copyright (C) 2004 author name <project_url>"}, false},
+		{"HTML", []string{`<section id="WTFPL">`, "<li>Copyright (C) 2004 author name &lt;project_url&gt;</li>"}, false},
+		{"pdf", []string{}, true},
+	}
+	for _, td := range testdata {
+		buffer := bytes.NewBuffer([]byte{})
+		err := notice.Write(buffer, td.giveFormat)
+		if (err != nil) != td.wontErr {
+			t.Errorf("%s: error did not match, wont %v, got %v", td.giveFormat, td.wontErr, err)
+		}
+		for _, wont := range td.wontContained {
+			if !strings.Contains(buffer.String(), wont) {
+				t.Errorf("%s: result did not contain %s", td.giveFormat, wont)
+			}
+		}
+	}
+}
+
+func TestNoticeGroupAnchor(t *testing.T) {
+	testdata := []struct {
+		giveLicense string
+		wontAnchor  string
+	}{
+		{"MIT", "MIT"},
+		{"GPL-2.0-only WITH Classpath-exception-2.0", "GPL-2.0-only-WITH-Classpath-exception-2.0"},
+	}
+	for _, td := range testdata {
+		group := &NoticeGroup{License: td.giveLicense}
+		if group.Anchor() != td.wontAnchor {
+			t.Errorf("anchor of %s did not match, wont %s, got %s", td.giveLicense, td.wontAnchor, group.Anchor())
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package lioss
+
+/*
+EventType shows the type of the events notified to Observer.
+*/
+type EventType int
+
+const (
+	/*ProjectOpened is notified when the identifier starts identifying the project.*/
+	ProjectOpened EventType = iota + 1
+	/*LicenseFileFound is notified for each license file found in the project, before identifying them.*/
+	LicenseFileFound
+	/*FileIdentified is notified when the license file is identified.*/
+	FileIdentified
+	/*ProjectIdentified is notified when the identifier finishes identifying the project, even if some errors occurred.*/
+	ProjectIdentified
+	/*ErrorOccurred is notified when the error occurred in identifying the project.*/
+	ErrorOccurred
+)
+
+func (et EventType) String() string {
+	switch et {
+	case ProjectOpened:
+		return "project-opened"
+	case LicenseFileFound:
+		return "license-file-found"
+	case FileIdentified:
+		return "file-identified"
+	case ProjectIdentified:
+		return "project-identified"
+	case ErrorOccurred:
+		return "error-occurred"
+	}
+	return "unknown"
+}
+
+/*
+Event shows an event in identifying the project.
+ID is the id of the license file for LicenseFileFound, and FileIdentified, File is the result of FileIdentified,
+Result is the result of ProjectIdentified, and Err is the error of ErrorOccurred.
+*/
+type Event struct {
+	Type    EventType
+	Project Project
+	ID      string
+	File    *FileResult
+	Result  *ProjectResult
+	Err     error
+}
+
+/*
+Observer is notified the events in identifying the projects (see Identifier.Observer).
+Since Identifier.IdentifyAll identifies the projects in parallel, Notify must be safe for concurrent use by multiple goroutines.
+*/
+type Observer interface {
+	Notify(event *Event)
+}
+
+/*
+ObserverFunc is an adapter to use the ordinary functions as Observer.
+*/
+type ObserverFunc func(event *Event)
+
+/*
+Notify calls f(event).
+*/
+func (f ObserverFunc) Notify(event *Event) {
+	f(event)
+}
+
+func (identifier *Identifier) notify(event *Event) {
+	if identifier.Observer != nil {
+		identifier.Observer.Notify(event)
+	}
+}
@@ -0,0 +1,54 @@
+package lioss
+
+import (
+	"context"
+	"testing"
+)
+
+func TestObserver(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	identifier, _ := NewIdentifier("6gram", 0.75, db)
+	events := []*Event{}
+	identifier.Observer = ObserverFunc(func(event *Event) {
+		events = append(events, event)
+	})
+	project, _ := NewProject("testdata/project3.jar")
+	defer project.Close()
+	identifier.IdentifyAll(context.Background(), []Project{project}, 1)
+
+	wontTypes := []EventType{ProjectOpened, LicenseFileFound, LicenseFileFound, FileIdentified, FileIdentified, ProjectIdentified}
+	if len(events) != len(wontTypes) {
+		t.Fatalf("size of events did not match, wont %d, got %d", len(wontTypes), len(events))
+	}
+	for i, event := range events {
+		if event.Type != wontTypes[i] {
+			t.Errorf("events[%d] did not match, wont %s, got %s", i, wontTypes[i], event.Type)
+		}
+		if event.Project != project {
+			t.Errorf("events[%d]: project did not match", i)
+		}
+	}
+	if events[3].File == nil || events[3].File.File.ID() != events[3].ID {
+		t.Errorf("file-identified event did not have the file result")
+	}
+	if events[5].Result == nil || len(events[5].Result.Files) != 2 {
+		t.Errorf("project-identified event did not have the project result")
+	}
+}
+
+func TestErrorOccurredEvent(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	identifier, _ := NewIdentifier("6gram", 0.75, db)
+	var got *Event
+	identifier.Observer = ObserverFunc(func(event *Event) {
+		if event.Type == ErrorOccurred {
+			got = event
+		}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	identifier.IdentifyProjectContext(ctx, NewTextProject("-", "MIT License"))
+	if got == nil || got.Err != context.Canceled {
+		t.Errorf("error-occurred event did not match, got %v", got)
+	}
+}
@@ -0,0 +1,246 @@
+package lioss
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+/*
+Verdict shows the result of evaluating a license by the policy.
+The greater verdict is the more severe.
+*/
+type Verdict int
+
+const (
+	/*Allowed shows the license is allowed by the policy.*/
+	Allowed Verdict = iota
+	/*ReviewRequired shows the license requires the review.*/
+	ReviewRequired
+	/*Denied shows the license is denied by the policy.*/
+	Denied
+)
+
+var verdictNames = []string{"allow", "review", "deny"}
+
+func (verdict Verdict) String() string {
+	if verdict < Allowed || verdict > Denied {
+		return "unknown"
+	}
+	return verdictNames[verdict]
+}
+
+/*
+ParseVerdict parses the given string ("allow", "review", or "deny") to Verdict.
+*/
+func ParseVerdict(name string) (Verdict, error) {
+	for i, verdictName := range verdictNames {
+		if strings.EqualFold(name, verdictName) {
+			return Verdict(i), nil
+		}
+	}
+	return Denied, fmt.Errorf("%s: unknown verdict, available values are allow, review, and deny", name)
+}
+
+/*
+Policy shows the rules for evaluating the identified licenses, such as allow/deny lists in CI.
+Allow, Review, and Deny are the lists of license patterns, which accept the wildcards ("*", and "?"), and are matched case-insensitively
+(e.g., "AGPL-*" matches both of "AGPL-3.0-only" and "AGPL-3.0-or-later").
+The patterns beginning with "category:" are matched with the category of the license (e.g., "category:network-copyleft", see Categories).
+If a license matches patterns in several lists, the most severe verdict is adopted.
+NoLicense is the verdict for the projects with no license files (default is "deny"),
+BelowThreshold is the verdict for the license files with no licenses above the threshold (default is "review"),
+and Default is the verdict for the licenses not listed (default is "review").
+*/
+type Policy struct {
+	Allow          []string `json:"allow,omitempty"`
+	Review         []string `json:"review,omitempty"`
+	Deny           []string `json:"deny,omitempty"`
+	NoLicense      string   `json:"no-license,omitempty"`
+	BelowThreshold string   `json:"below-threshold,omitempty"`
+	Default        string   `json:"default,omitempty"`
+}
+
+/*
+Violation shows a license file (or a project) which is not allowed by the policy.
+Rule shows the reason of the verdict, that is, the matched pattern, "no-license", "below-threshold", or "default".
+*/
+type Violation struct {
+	Project string  `json:"project"`
+	File    string  `json:"file,omitempty"`
+	License string  `json:"license,omitempty"`
+	Verdict Verdict `json:"-"`
+	Rule    string  `json:"rule"`
+}
+
+func (violation *Violation) String() string {
+	target := violation.Project
+	if violation.File != "" {
+		target = fmt.Sprintf("%s/%s", violation.Project, violation.File)
+	}
+	if violation.License == "" {
+		return fmt.Sprintf("%s: %s (%s)", violation.Verdict, target, violation.Rule)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", violation.Verdict, target, violation.License, violation.Rule)
+}
+
+/*
+ReadPolicy reads the policy from the JSON file of the given path.
+*/
+func ReadPolicy(path string) (*Policy, error) {
+	reader, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	policy, err := ParsePolicy(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	return policy, nil
+}
+
+/*
+ParsePolicy parses the policy in JSON format from the given reader, and validates it.
+*/
+func ParsePolicy(reader io.Reader) (*Policy, error) {
+	policy := new(Policy)
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(policy); err != nil {
+		return nil, err
+	}
+	return policy, policy.validate()
+}
+
+func (policy *Policy) validate() error {
+	for _, rule := range []string{policy.NoLicense, policy.BelowThreshold, policy.Default} {
+		if _, err := verdictOf(rule, Allowed); err != nil {
+			return err
+		}
+	}
+	for _, patterns := range [][]string{policy.Allow, policy.Review, policy.Deny} {
+		for _, pattern := range patterns {
+			if err := validatePattern(pattern); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validatePattern(pattern string) error {
+	if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+		return fmt.Errorf("%s: invalid pattern", pattern)
+	}
+	category, ok := categoryPattern(pattern)
+	if ok && !strings.ContainsAny(category, "*?[") {
+		_, err := ParseCategory(category)
+		return err
+	}
+	return nil
+}
+
+/*
+categoryPattern returns the category part of the given pattern, if the pattern is for categories (e.g., "category:weak-copyleft").
+*/
+func categoryPattern(pattern string) (string, bool) {
+	if !strings.HasPrefix(strings.ToLower(pattern), categoryPrefix) {
+		return "", false
+	}
+	return pattern[len(categoryPrefix):], true
+}
+
+const categoryPrefix = "category:"
+
+func verdictOf(rule string, defaultVerdict Verdict) (Verdict, error) {
+	if rule == "" {
+		return defaultVerdict, nil
+	}
+	return ParseVerdict(rule)
+}
+
+/*
+Evaluate evaluates the identified licenses of the given project by the policy, and returns the violations,
+that is, the license files (and the project) whose verdicts are not Allowed.
+The most probable license of each license file is evaluated.
+*/
+func (policy *Policy) Evaluate(result *ProjectResult) []*Violation {
+	project := result.Project.BasePath()
+	if len(result.Files) == 0 {
+		return appendViolation([]*Violation{}, &Violation{Project: project, Verdict: policy.verdict(policy.NoLicense, Denied), Rule: "no-license"})
+	}
+	violations := []*Violation{}
+	for _, file := range result.Files {
+		violation := &Violation{Project: project, File: file.File.ID(), Verdict: policy.verdict(policy.BelowThreshold, ReviewRequired), Rule: "below-threshold"}
+		if len(file.Results) > 0 {
+			violation.License = file.Results[0].Expression()
+			violation.Verdict, violation.Rule = policy.EvaluateLicense(file.Results[0])
+		}
+		violations = appendViolation(violations, violation)
+	}
+	return violations
+}
+
+func appendViolation(violations []*Violation, violation *Violation) []*Violation {
+	if violation.Verdict == Allowed {
+		return violations
+	}
+	return append(violations, violation)
+}
+
+func (policy *Policy) verdict(rule string, defaultVerdict Verdict) Verdict {
+	verdict, _ := verdictOf(rule, defaultVerdict)
+	return verdict
+}
+
+/*
+EvaluateLicense evaluates the given result by the policy, and returns the verdict and the matched rule.
+Both of the license name and the expression (e.g., "GPL-2.0-only WITH Classpath-exception-2.0") are tested for the patterns.
+*/
+func (policy *Policy) EvaluateLicense(result *Result) (Verdict, string) {
+	lists := []struct {
+		verdict  Verdict
+		patterns []string
+	}{
+		{Denied, policy.Deny}, {ReviewRequired, policy.Review}, {Allowed, policy.Allow},
+	}
+	for _, list := range lists {
+		if pattern, ok := findPattern(list.patterns, result); ok {
+			return list.verdict, pattern
+		}
+	}
+	return policy.verdict(policy.Default, ReviewRequired), "default"
+}
+
+func findPattern(patterns []string, result *Result) (string, bool) {
+	for _, pattern := range patterns {
+		for _, name := range targetNames(pattern, result) {
+			if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name)); matched {
+				return pattern, true
+			}
+		}
+	}
+	return "", false
+}
+
+/*
+targetNames returns the names of the given result tested for the given pattern,
+that is, the category for the category patterns, or the license name and the expression for others.
+*/
+func targetNames(pattern string, result *Result) []string {
+	if _, ok := categoryPattern(pattern); !ok {
+		return []string{result.Name, result.Expression()}
+	}
+	classification := result.Classification
+	if classification == nil {
+		classification = ClassifyResult(result)
+	}
+	if classification == nil {
+		return []string{}
+	}
+	return []string{categoryPrefix + string(classification.Category)}
+}
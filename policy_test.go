@@ -0,0 +1,117 @@
+package lioss
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePolicy(t *testing.T) {
+	testdata := []struct {
+		giveJSON string
+		wontErr  bool
+	}{
+		{`{"allow": ["MIT", "BSD-*"], "deny": ["AGPL-*"], "no-license": "review"}`, false},
+		{`{}`, false},
+		{`{"default": "ignore"}`, true},
+		{`{"deny": ["GPL-[2"]}`, true},
+		{`{"unknown": ["MIT"]}`, true},
+		{`{"allow": "MIT"}`, true},
+		{`{"deny": ["category:network-copyleft", "category:*"]}`, false},
+		{`{"deny": ["category:copyleft"]}`, true},
+	}
+	for _, td := range testdata {
+		_, err := ParsePolicy(strings.NewReader(td.giveJSON))
+		if (err != nil) != td.wontErr {
+			t.Errorf("ParsePolicy(%s) error did not match, wont %v, got %v", td.giveJSON, td.wontErr, err)
+		}
+	}
+}
+
+func TestEvaluateLicense(t *testing.T) {
+	policy := &Policy{Allow: []string{"MIT", "BSD-*", "GPL-2.0-only WITH Classpath-exception-2.0"}, Review: []string{"LGPL-*"}, Deny: []string{"*GPL-*"}}
+	testdata := []struct {
+		giveResult  *Result
+		wontVerdict Verdict
+		wontRule    string
+	}{
+		{&Result{Name: "MIT"}, Allowed, "MIT"},
+		{&Result{Name: "bsd-3-clause"}, Allowed, "BSD-*"},
+		{&Result{Name: "AGPL-3.0-only"}, Denied, "*GPL-*"},
+		{&Result{Name: "LGPL-2.1-only"}, Denied, "*GPL-*"},
+		{&Result{Name: "GPL-2.0-only", Exception: "Classpath-exception-2.0"}, Denied, "*GPL-*"},
+		{&Result{Name: "Apache-2.0"}, ReviewRequired, "default"},
+	}
+	for _, td := range testdata {
+		verdict, rule := policy.EvaluateLicense(td.giveResult)
+		if verdict != td.wontVerdict || rule != td.wontRule {
+			t.Errorf("EvaluateLicense(%s) did not match, wont %s (%s), got %s (%s)", td.giveResult.Expression(), td.wontVerdict, td.wontRule, verdict, rule)
+		}
+	}
+}
+
+func TestEvaluateCategory(t *testing.T) {
+	policy := &Policy{Allow: []string{"category:permissive", "category:public-domain"}, Review: []string{"category:weak-copyleft"}, Deny: []string{"category:*-copyleft", "category:source-available"}}
+	testdata := []struct {
+		giveResult  *Result
+		wontVerdict Verdict
+		wontRule    string
+	}{
+		{&Result{Name: "MIT"}, Allowed, "category:permissive"},
+		{&Result{Name: "Unlicense"}, Allowed, "category:public-domain"},
+		{&Result{Name: "LGPL-3.0-only"}, Denied, "category:*-copyleft"},
+		{&Result{Name: "AGPL-3.0-only"}, Denied, "category:*-copyleft"},
+		{&Result{Name: "GPL-2.0-only", Exception: "Classpath-exception-2.0", Classification: &Classification{Category: WeakCopyleft}}, Denied, "category:*-copyleft"},
+		{&Result{Name: "BUSL-1.1"}, Denied, "category:source-available"},
+		{&Result{Name: "Unknown-License"}, ReviewRequired, "default"},
+	}
+	for _, td := range testdata {
+		verdict, rule := policy.EvaluateLicense(td.giveResult)
+		if verdict != td.wontVerdict || rule != td.wontRule {
+			t.Errorf("EvaluateLicense(%s) did not match, wont %s (%s), got %s (%s)", td.giveResult.Expression(), td.wontVerdict, td.wontRule, verdict, rule)
+		}
+	}
+	reviewPolicy := &Policy{Allow: []string{"category:permissive"}, Review: []string{"category:weak-copyleft"}}
+	if verdict, _ := reviewPolicy.EvaluateLicense(&Result{Name: "GPL-2.0-only", Exception: "Classpath-exception-2.0"}); verdict != ReviewRequired {
+		t.Errorf("GPL-2.0-only WITH Classpath-exception-2.0 should be weak copyleft, got %s", verdict)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	identifier, _ := NewIdentifier("5gram", 0.75, db)
+	policy := &Policy{Allow: []string{"Apache-*", "WTFPL"}, Deny: []string{"GPLv*"}, Default: "allow"}
+	testdata := []struct {
+		givePath     string
+		wontVerdicts []Verdict
+		wontRules    []string
+	}{
+		{"testdata/project1", []Verdict{}, []string{}},
+		{"testdata/project2", []Verdict{Denied}, []string{"GPLv*"}},
+		{"testdata/project4", []Verdict{Denied}, []string{"no-license"}},
+	}
+	for _, td := range testdata {
+		project, _ := NewProject(td.givePath)
+		violations := policy.Evaluate(identifier.IdentifyProject(project))
+		project.Close()
+		if len(violations) != len(td.wontVerdicts) {
+			t.Errorf("%s: size of violations did not match, wont %d, got %d", td.givePath, len(td.wontVerdicts), len(violations))
+			continue
+		}
+		for i, violation := range violations {
+			if violation.Verdict != td.wontVerdicts[i] || violation.Rule != td.wontRules[i] {
+				t.Errorf("%s: violation did not match, wont %s (%s), got %s", td.givePath, td.wontVerdicts[i], td.wontRules[i], violation)
+			}
+		}
+	}
+}
+
+func TestEvaluateBelowThreshold(t *testing.T) {
+	db, _ := ReadDatabase("testdata/test.liossgz")
+	identifier, _ := NewIdentifier("5gram", 1.0, db)
+	project, _ := NewProject("testdata/project1")
+	defer project.Close()
+	violations := (&Policy{}).Evaluate(identifier.IdentifyProject(project))
+	if len(violations) != 1 || violations[0].Verdict != ReviewRequired || violations[0].Rule != "below-threshold" {
+		t.Errorf("violations did not match, got %v", violations)
+	}
+}
@@ -34,6 +34,16 @@ type Project interface {
 	LicenseFile(licenseID string) (LicenseFile, error)
 }
 
+/*
+NoticeFinder is an optional interface of Project for finding NOTICE files (e.g., NOTICE of the Apache License 2.0) in the project.
+*/
+type NoticeFinder interface {
+	/* NoticeIDs returns the ids for NOTICE files in the project. */
+	NoticeIDs() []string
+	/* NoticeFile returns the NOTICE file of the given id. */
+	NoticeFile(noticeID string) (LicenseFile, error)
+}
+
 /*
 NewProject creates an instance of Project.
 Acceptable file formats of this function is zip/jar/war file, and directory.
@@ -55,10 +65,10 @@ func newFileProject(path string) (Project, error) {
 		return nil, err
 	}
 	if kind.MIME.Value == "application/zip" {
-		return &zipProject{path: path}, nil
+		return newZipProject(path), nil
 	}
 	if isLicenseFile(filepath.Base(path)) {
-		return &dirProject{baseDir: filepath.Dir(path), licensePaths: []string{filepath.Base(path)}}, nil
+		return newLicenseFileProject(path), nil
 	}
 	return nil, fmt.Errorf("%s: unknown project format", path)
 }
@@ -96,12 +106,35 @@ func (blf *basicLicenseFile) String() string {
 	return blf.ID()
 }
 
-func isContainOtherWord(fileName string) bool {
+func isContainOtherWord(fileName, word string) bool {
 	ext := filepath.Ext(fileName)
-	return len(fileName) > len(ext)+len("license")
+	return len(fileName) > len(ext)+len(word)
 }
 
 func isLicenseFile(path string) bool {
 	fileName := strings.ToLower(filepath.Base(path))
-	return strings.HasPrefix(fileName, "license") && !isContainOtherWord(fileName)
+	return strings.HasPrefix(fileName, "license") && !isContainOtherWord(fileName, "license")
+}
+
+func isNoticeFile(path string) bool {
+	fileName := strings.ToLower(filepath.Base(path))
+	return strings.HasPrefix(fileName, "notice") && !isContainOtherWord(fileName, "notice")
+}
+
+func isReadmeFile(path string) bool {
+	fileName := strings.ToLower(filepath.Base(path))
+	return strings.HasPrefix(fileName, "readme") && !isContainOtherWord(fileName, "readme")
+}
+
+/*
+sourceExtensions shows the extensions of the source files whose headers may contain the version grant statements.
+*/
+var sourceExtensions = map[string]bool{
+	".c": true, ".h": true, ".cc": true, ".cpp": true, ".cxx": true, ".hpp": true, ".go": true, ".java": true, ".kt": true, ".scala": true,
+	".py": true, ".rb": true, ".pl": true, ".pm": true, ".php": true, ".js": true, ".ts": true, ".rs": true, ".cs": true, ".swift": true,
+	".m": true, ".sh": true, ".el": true, ".lisp": true, ".scm": true, ".hs": true, ".ml": true, ".lua": true, ".tcl": true, ".vala": true,
+}
+
+func isGrantFile(path string) bool {
+	return isReadmeFile(path) || sourceExtensions[strings.ToLower(filepath.Ext(path))]
 }
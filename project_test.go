@@ -1,26 +1,10 @@
 package lioss
 
-import "testing"
-
-func TestRemoveBasePath(t *testing.T) {
-	testdata := []struct {
-		giveBasePath string
-		givePath     string
-		wontResult   string
-	}{
-		{"./testdata/project1", "./testdata/project1/LICENSE", "LICENSE"},
-		{"./testdata/project1", "testdata/project1/LICENSE", "LICENSE"},
-		{"testdata/project1", "./testdata/project1/LICENSE", "LICENSE"},
-		{"testdata/project3", "testdata/project3/subproject/license", "subproject/license"},
-		{"testdata/project3/subproject", "testdata/project2", "project2"},
-	}
-	for _, td := range testdata {
-		gotResult := removeBasePath(td.giveBasePath, td.givePath)
-		if !isSamePath(gotResult, td.wontResult) {
-			t.Errorf("result of removeBasePath(%s, %s) did not match, wont %s, got %s", td.giveBasePath, td.givePath, td.wontResult, gotResult)
-		}
-	}
-}
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
 
 func TestIsLicenseFile(t *testing.T) {
 	testdata := []struct {
@@ -43,32 +27,84 @@ func TestIsLicenseFile(t *testing.T) {
 
 func TestFindLicenseFile(t *testing.T) {
 	testdata := []struct {
-		basePath     string
+		name         string
+		fsys         fstest.MapFS
 		licensePaths []string
 	}{
-		{"testdata/project1", []string{"LICENSE"}},
-		{"testdata/project2", []string{"license.txt"}},
-		{"testdata/project3", []string{"license", "subproject/license"}},
-		{"testdata/project4", []string{}},
-		{"testdata/project3.jar", []string{"project3/license", "project3/subproject/license"}},
-		{"LICENSE", []string{"LICENSE"}},
+		{"project1", fstest.MapFS{"LICENSE": {Data: []byte("MIT License")}, "README.md": {Data: []byte("# project1")}}, []string{"LICENSE"}},
+		{"project2", fstest.MapFS{"license.txt": {Data: []byte("GPL")}}, []string{"license.txt"}},
+		{"project3", fstest.MapFS{"subproject/license": {Data: []byte("BSD")}, "license": {Data: []byte("Apache")}}, []string{"license", "subproject/license"}},
+		{"project4", fstest.MapFS{"src/main.go": {Data: []byte("package main")}}, []string{}},
 	}
 
 	for _, td := range testdata {
-		project, _ := NewProject(td.basePath)
+		project := NewFSProject(td.name, td.fsys)
 		defer project.Close()
-		if len(project.LicenseIDs()) != len(td.licensePaths) {
-			t.Errorf("%s: length of license path did not match, wont %d, got %d", td.basePath, len(td.licensePaths), len(project.LicenseIDs()))
+		if !reflect.DeepEqual(project.LicenseIDs(), td.licensePaths) {
+			t.Errorf("%s: license paths did not match, wont %v, got %v", td.name, td.licensePaths, project.LicenseIDs())
 		}
-		for i, id := range project.LicenseIDs() {
-			if !isSamePath(td.licensePaths[i], id) {
-				t.Errorf("license did not match: wont %s, got %s", td.licensePaths[i], id)
-			}
+		for _, id := range project.LicenseIDs() {
 			file, err := project.LicenseFile(id)
-			defer file.Close()
 			if err != nil {
-				t.Errorf("project.LicnseFile(%s) failed: %s", id, err.Error())
+				t.Errorf("project.LicenseFile(%s) failed: %s", id, err.Error())
+				continue
 			}
+			file.Close()
+		}
+	}
+}
+
+func TestLicenseFileProject(t *testing.T) {
+	project, err := NewProject("LICENSE")
+	if err != nil {
+		t.Fatalf("LICENSE: NewProject failed: %s", err.Error())
+	}
+	defer project.Close()
+	if ids := project.LicenseIDs(); !reflect.DeepEqual(ids, []string{"LICENSE"}) {
+		t.Errorf("LICENSE: license paths did not match, wont [LICENSE], got %v", ids)
+	}
+}
+
+func TestFindNoticeFile(t *testing.T) {
+	testdata := []struct {
+		basePath    string
+		noticePaths []string
+	}{
+		{"testdata/project1", []string{}},
+		{"testdata/project3", []string{"NOTICE"}},
+		{"testdata/project3.jar", []string{}},
+	}
+	for _, td := range testdata {
+		project, _ := NewProject(td.basePath)
+		defer project.Close()
+		finder, ok := project.(NoticeFinder)
+		if !ok {
+			t.Fatalf("%s: project is not NoticeFinder", td.basePath)
+		}
+		if !reflect.DeepEqual(finder.NoticeIDs(), td.noticePaths) {
+			t.Errorf("%s: notice paths did not match, wont %v, got %v", td.basePath, td.noticePaths, finder.NoticeIDs())
+		}
+	}
+}
+
+func TestFindGrantFile(t *testing.T) {
+	testdata := []struct {
+		basePath   string
+		grantPaths []string
+	}{
+		{"testdata/project1", []string{}},
+		{"testdata/project5", []string{"README.md", "src/hello.c"}},
+		{"testdata/project3.jar", []string{}},
+	}
+	for _, td := range testdata {
+		project, _ := NewProject(td.basePath)
+		defer project.Close()
+		finder, ok := project.(GrantFinder)
+		if !ok {
+			t.Fatalf("%s: project is not GrantFinder", td.basePath)
+		}
+		if !reflect.DeepEqual(finder.GrantIDs(), td.grantPaths) {
+			t.Errorf("%s: grant paths did not match, wont %v, got %v", td.basePath, td.grantPaths, finder.GrantIDs())
 		}
 	}
 }
@@ -0,0 +1,94 @@
+package lioss
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+/*
+AlgorithmEntry shows an algorithm registered in the registry.
+Name and Description are shown in the help messages, and Match decides whether the given lower-cased algorithm name (e.g., "5gram") is of the entry.
+New creates the instance of Algorithm from the given algorithm name, which parses the parameters in the name, if needed.
+BuildNames are the algorithm names built into the databases by mkliossdb and spdx2liossdb.
+The entry without BuildNames (e.g., ensemble) uses the entries of other algorithms.
+*/
+type AlgorithmEntry struct {
+	Name             string
+	Description      string
+	DefaultThreshold float64
+	Match            func(name string) bool
+	New              func(name string) (Algorithm, error)
+	BuildNames       []string
+}
+
+/*
+AvailableAlgorithms contains the names of algorithms built into the databases, by the registration order.
+RegisterAlgorithm appends BuildNames of the registered algorithm, therefore, the users must not modify it.
+*/
+var AvailableAlgorithms = []string{}
+
+var registry = struct {
+	mutex   sync.RWMutex
+	entries []*AlgorithmEntry
+}{}
+
+/*
+RegisterAlgorithm registers the given algorithm into the registry.
+The registered algorithms are available in NewAlgorithm, NewIdentifier, and the database builders.
+This function returns an error if the entry lacks Name, Match or New, or the entry of the same name is already registered.
+*/
+func RegisterAlgorithm(entry *AlgorithmEntry) error {
+	if entry == nil || entry.Name == "" || entry.Match == nil || entry.New == nil {
+		return fmt.Errorf("algorithm entry requires Name, Match, and New")
+	}
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	for _, registered := range registry.entries {
+		if registered.Name == entry.Name {
+			return fmt.Errorf("%s: algorithm already registered", entry.Name)
+		}
+	}
+	registry.entries = append(registry.entries, entry)
+	AvailableAlgorithms = append(AvailableAlgorithms, entry.BuildNames...)
+	return nil
+}
+
+/*
+RegisteredAlgorithms returns the registered algorithms in the registration order.
+*/
+func RegisteredAlgorithms() []*AlgorithmEntry {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return append([]*AlgorithmEntry{}, registry.entries...)
+}
+
+/*
+FindAlgorithm finds the registered algorithm matching the given algorithm name.
+*/
+func FindAlgorithm(name string) (*AlgorithmEntry, bool) {
+	lowerName := strings.ToLower(name)
+	for _, entry := range RegisteredAlgorithms() {
+		if entry.Match(lowerName) {
+			return entry, true
+		}
+	}
+	return nil, false
+}
+
+/*
+DefaultThreshold returns the default threshold of the given algorithm name.
+If the algorithm is not found, this function returns 0.75.
+*/
+func DefaultThreshold(name string) float64 {
+	if entry, ok := FindAlgorithm(name); ok && entry.DefaultThreshold > 0 {
+		return entry.DefaultThreshold
+	}
+	return 0.75
+}
+
+func matchPattern(pattern string) func(name string) bool {
+	regexp := regexp.MustCompile(pattern)
+	return regexp.MatchString
+}
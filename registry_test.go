@@ -0,0 +1,80 @@
+package lioss
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+type constantAlgorithm struct {
+	nGram
+}
+
+func (ca *constantAlgorithm) String() string {
+	return "constant"
+}
+
+func (ca *constantAlgorithm) Parse(reader io.Reader, licenseName string) (*License, error) {
+	return newLicense(licenseName, map[string]int{"constant": 1}), nil
+}
+
+func TestRegisterAlgorithm(t *testing.T) {
+	entry := &AlgorithmEntry{Name: "constant", Description: "always same.", DefaultThreshold: 0.5,
+		Match: func(name string) bool { return name == "constant" },
+		New:   func(string) (Algorithm, error) { return &constantAlgorithm{}, nil }, BuildNames: []string{"constant"}}
+	if err := RegisterAlgorithm(entry); err != nil {
+		t.Fatalf("register failed: %s", err.Error())
+	}
+	defer func() {
+		registry.entries = registry.entries[:len(registry.entries)-1]
+		AvailableAlgorithms = AvailableAlgorithms[:len(AvailableAlgorithms)-1]
+	}()
+	if err := RegisterAlgorithm(entry); err == nil {
+		t.Errorf("duplicated registration should be failed")
+	}
+	if names := AvailableAlgorithms; names[len(names)-1] != "constant" {
+		t.Errorf("registered algorithm was not available, got %v", names)
+	}
+	if DefaultThreshold("CONSTANT") != 0.5 {
+		t.Errorf("default threshold did not match, wont 0.5, got %f", DefaultThreshold("constant"))
+	}
+	db := NewDatabase()
+	db.Put("constant", newLicense("license", map[string]int{"constant": 1}))
+	identifier, err := NewIdentifier("constant", 0.5, db)
+	if err != nil {
+		t.Fatalf("NewIdentifier failed: %s", err.Error())
+	}
+	license, _ := identifier.Comparator.Parse(nil, "LICENSE")
+	results, _ := identifier.identify(context.Background(), license)
+	if len(results) != 1 || results[0].Name != "license" {
+		t.Errorf("identify by registered algorithm did not match, got %v", results)
+	}
+}
+
+func TestRegisterInvalidAlgorithm(t *testing.T) {
+	testdata := []*AlgorithmEntry{
+		nil,
+		{Name: "", Match: matchPattern("^x$"), New: createBM25Algorithm},
+		{Name: "x", New: createBM25Algorithm},
+		{Name: "x", Match: matchPattern("^x$")},
+		{Name: "kgram", Match: matchPattern("^x$"), New: createBM25Algorithm},
+	}
+	for _, td := range testdata {
+		if err := RegisterAlgorithm(td); err == nil {
+			t.Errorf("RegisterAlgorithm(%v) should be failed", td)
+		}
+	}
+}
+
+func TestAvailableAlgorithms(t *testing.T) {
+	wont := []string{"1gram", "2gram", "3gram", "4gram", "5gram", "6gram", "7gram", "8gram", "9gram", "wordfreq", "tfidf", "3shingle", "3shingle-containment", "bm25"}
+	got := AvailableAlgorithms
+	if len(got) != len(wont) {
+		t.Fatalf("size of available algorithms did not match, wont %v, got %v", wont, got)
+	}
+	for i := range wont {
+		if wont[i] != got[i] {
+			t.Errorf("available algorithms[%d] did not match, wont %s, got %s", i, wont[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package lioss
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+/*
+shingle is an implementation type of Algorithm by word-level shingles (w-shingling).
+The shingles are the sequences of k words, therefore, the shingles are robust against the changes of punctuations and whitespaces.
+If containmentFlag is true, Compare returns the containment of the license in the database,
+otherwise, returns the (weighted) Jaccard similarity.
+*/
+type shingle struct {
+	kValue          int
+	containmentFlag bool
+}
+
+/*
+newShingle creates an instance of k-shingle.
+*/
+func newShingle(k int, containmentFlag bool) *shingle {
+	return &shingle{kValue: k, containmentFlag: containmentFlag}
+}
+
+func (shingle *shingle) String() string {
+	if shingle.containmentFlag {
+		return fmt.Sprintf("%dshingle-containment", shingle.kValue)
+	}
+	return fmt.Sprintf("%dshingle", shingle.kValue)
+}
+
+/*
+Indexable of shingle returns true only for the Jaccard similarity,
+since the containment scores the license embedded in a larger file high, even if the Jaccard similarity is low.
+*/
+func (shingle *shingle) Indexable() bool {
+	return !shingle.containmentFlag
+}
+
+/*
+Prepare of shingle do nothing.
+*/
+func (shingle *shingle) Prepare(db *Database) error {
+	return nil
+}
+
+/*
+Parse parses given data and create an instance of License by k-shingles.
+*/
+func (shingle *shingle) Parse(reader io.Reader, licenseName string) (*License, error) {
+	result, err := readFully(reader)
+	if err != nil {
+		return nil, err
+	}
+	return shingle.buildShingles(result, licenseName), nil
+}
+
+func (shingle *shingle) buildShingles(document, licenseName string) *License {
+	words := strings.Fields(preprocessForWordFreq(document))
+	freq := map[string]int{}
+	length := len(words) - shingle.kValue + 1
+	for i := 0; i < length; i++ {
+		freq[strings.Join(words[i:i+shingle.kValue], " ")]++
+	}
+	if length <= 0 && len(words) > 0 {
+		freq[strings.Join(words, " ")]++
+	}
+	return newLicense(licenseName, freq)
+}
+
+/*
+Compare computes similarity between given two licenses.
+In the containment mode, the resultant value shows how much of license2 (the license in the database) is contained in license1,
+therefore, a short license embedded in the long document (e.g., README) still scores high.
+*/
+func (shingle *shingle) Compare(license1, license2 *License) float64 {
+	if shingle.containmentFlag {
+		return license1.containment(license2)
+	}
+	return license1.jaccard(license2)
+}
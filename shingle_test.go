@@ -0,0 +1,55 @@
+package lioss
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestShingle(t *testing.T) {
+	testdata := []struct {
+		givenData string
+		kValue    int
+		results   map[string]int
+	}{
+		{"a b c d", 2, map[string]int{"a b": 1, "b c": 1, "c d": 1}},
+		{"The MIT, the MIT.", 2, map[string]int{"the mit": 2, "mit the": 1}},
+		{"a b", 3, map[string]int{"a b": 1}},
+	}
+	for _, td := range testdata {
+		license, err := newShingle(td.kValue, false).Parse(strings.NewReader(td.givenData), "unknown-license")
+		if err != nil {
+			t.Errorf("parse failed: %s", err.Error())
+		}
+		if len(license.Frequencies) != len(td.results) {
+			t.Errorf("map size did not match, wont %d, got %d (%v)", len(td.results), len(license.Frequencies), license.Frequencies)
+		}
+		for key, wontValue := range td.results {
+			if gotValue := license.Frequencies[key]; gotValue != wontValue {
+				t.Errorf("value did not match, wont: shingle[%s]: %d, got shingle[%s]: %d", key, wontValue, key, gotValue)
+			}
+		}
+	}
+}
+
+func TestShingleContainment(t *testing.T) {
+	data, _ := ioutil.ReadFile("data/misc/MIT")
+	readme := "# Project\n\nThis is a sample project, which has a long description.\n\n## License\n\n" + string(data) + "\n## Usage\n\nRun the command with some options.\n"
+	testdata := []struct {
+		containmentFlag bool
+		low             float64
+		high            float64
+	}{
+		{true, 0.99, 1.0},
+		{false, 0.0, 0.9},
+	}
+	for _, td := range testdata {
+		algorithm := newShingle(3, td.containmentFlag)
+		target, _ := algorithm.Parse(strings.NewReader(readme), "README")
+		license, _ := algorithm.Parse(strings.NewReader(string(data)), "MIT")
+		got := algorithm.Compare(target, license)
+		if got < td.low || got > td.high {
+			t.Errorf("%s: similarity did not in range, wont %f to %f, got %f", algorithm.String(), td.low, td.high, got)
+		}
+	}
+}
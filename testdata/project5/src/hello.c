@@ -0,0 +1,11 @@
+/*
+ * hello.c
+ *
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+#include <stdio.h>
+
+int main(void) {
+    printf("hello\n");
+    return 0;
+}
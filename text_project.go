@@ -0,0 +1,60 @@
+package lioss
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+/*
+TextID is the id of the license file of the text projects (see NewTextProject).
+*/
+const TextID = "LICENSE"
+
+/*
+textProject is an instance of Project, which has a license text in memory.
+*/
+type textProject struct {
+	basePath string
+	text     string
+}
+
+/*
+NewTextProject creates an instance of Project having the given license text as the license file of TextID.
+basePath is used only for showing the project (e.g., "-" for stdin).
+The text projects have no NOTICE files, and no files for the version grant statements of the GNU licenses.
+*/
+func NewTextProject(basePath, text string) Project {
+	return &textProject{basePath: basePath, text: text}
+}
+
+/*
+BasePath returns the path of the project.
+*/
+func (tp *textProject) BasePath() string {
+	return tp.basePath
+}
+
+/*
+Close closes project.
+*/
+func (tp *textProject) Close() error {
+	return nil
+}
+
+/*
+LicenseIDs returns ids containing the project for LicenseFile method.
+*/
+func (tp *textProject) LicenseIDs() []string {
+	return []string{TextID}
+}
+
+/*
+LicenseFile returns the license file of the given id.
+*/
+func (tp *textProject) LicenseFile(licenseID string) (LicenseFile, error) {
+	if licenseID != TextID {
+		return nil, fmt.Errorf("%s: not found", licenseID)
+	}
+	return &basicLicenseFile{id: licenseID, reader: ioutil.NopCloser(strings.NewReader(tp.text))}, nil
+}
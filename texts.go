@@ -0,0 +1,36 @@
+package lioss
+
+/*
+PutText stores the canonical text of the given license into the database.
+The texts are optional, and used for displaying the matched texts and the differences in the reports.
+*/
+func (db *Database) PutText(licenseName, text string) {
+	if db.Texts == nil {
+		db.Texts = map[string]string{}
+	}
+	db.Texts[licenseName] = text
+}
+
+/*
+Text returns the canonical text of the given license, and false if the database has no text of the license.
+*/
+func (db *Database) Text(licenseName string) (string, bool) {
+	text, ok := db.Texts[licenseName]
+	return text, ok
+}
+
+/*
+mergeTexts merges the texts of both databases, and the texts of the first one have priority.
+*/
+func mergeTexts(texts, other map[string]string) map[string]string {
+	if len(texts) == 0 && len(other) == 0 {
+		return nil
+	}
+	results := map[string]string{}
+	for _, source := range []map[string]string{other, texts} {
+		for name, text := range source {
+			results[name] = text
+		}
+	}
+	return results
+}
@@ -9,7 +9,8 @@ import (
 tfidf is an implementation type of Algorithm.
 */
 type tfidf struct {
-	data map[string]*document
+	data        map[string]*document
+	frequencies map[string]int
 }
 
 type document struct {
@@ -62,21 +63,35 @@ func (val *value) tfidf() float64 {
 NewTfidf creates an instance of Tfidf.
 */
 func newTfidf() *tfidf {
-	return &tfidf{data: map[string]*document{}}
+	return &tfidf{data: map[string]*document{}, frequencies: map[string]int{}}
 }
 
 func (tfidf *tfidf) String() string {
 	return "tfidf"
 }
 
+/*
+Indexable of Tfidf returns true, since the similar licenses by tf-idf share many terms.
+*/
+func (tfidf *tfidf) Indexable() bool {
+	return true
+}
+
+/*
+countDocument returns the number of documents containing the given word.
+The document frequencies are computed in Prepare, therefore, this method does not scan the documents.
+*/
 func (tfidf *tfidf) countDocument(word string) int {
-	count := 0
+	return tfidf.frequencies[word]
+}
+
+func countDocumentFrequencies(tfidf *tfidf) {
+	tfidf.frequencies = map[string]int{}
 	for _, document := range tfidf.data {
-		if document.contains(word) {
-			count++
+		for word := range document.words {
+			tfidf.frequencies[word]++
 		}
 	}
-	return count
 }
 
 func calculateTfidf(tfidf *tfidf, word string, count, total int) *value {
@@ -115,6 +130,7 @@ func (tfidf *tfidf) Prepare(db *Database) error {
 	for _, license := range licenses {
 		updateLicense(tfidf, license)
 	}
+	countDocumentFrequencies(tfidf)
 	calculateAllOfTfidf(tfidf)
 	return nil
 }
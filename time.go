@@ -30,3 +30,8 @@ func (t *Time) UnmarshalJSON(data []byte) error {
 func (t *Time) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf(`"%s"`, t.format())), nil
 }
+
+/*String returns the time in the same format of JSON.*/
+func (t *Time) String() string {
+	return t.format()
+}
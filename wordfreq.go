@@ -22,6 +22,13 @@ func (wfreq *wordFreq) String() string {
 	return "wordfreq"
 }
 
+/*
+Indexable of WordFreq returns true, since the similar licenses by word frequencies share many terms.
+*/
+func (wfreq *wordFreq) Indexable() bool {
+	return true
+}
+
 /*
 Prepare of WordFreq do nothing.
 */